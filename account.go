@@ -0,0 +1,272 @@
+package zinc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	zkscrypto "github.com/zksync-sdk/zksync-sdk-go"
+)
+
+// AccountState is the result of account_info.
+type AccountState struct {
+	Address string `json:"address"`
+	// Id is nil until the account is created by its first deposit or
+	// transfer.
+	Id        *uint64         `json:"id"`
+	Committed AccountSnapshot `json:"committed"`
+	Verified  AccountSnapshot `json:"verified"`
+}
+
+// AccountSnapshot is an account as of the committed or the verified state.
+// Balances are keyed by token symbol.
+type AccountSnapshot struct {
+	Balances   map[string]BigInt `json:"balances"`
+	Nonce      uint64            `json:"nonce"`
+	PubKeyHash string            `json:"pubKeyHash"`
+}
+
+// AccountInfo looks up the account of address with account_info. The
+// committed nonce is the one to use for the next transaction.
+func (c *Client) AccountInfo(ctx context.Context, address string) (*AccountState, error) {
+	var state AccountState
+	if err := c.call(ctx, "account_info", []interface{}{address}, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// AccountIdForPubKeyHash returns the id of the account whose L2 key hashes to
+// pkh, a "sync:" pubkey hash. The standard zkSync API only indexes accounts by
+// address, so this needs a node or indexer serving the
+// account_id_by_pub_key_hash method; use AccountInfo when the address is
+// known.
+func (c *Client) AccountIdForPubKeyHash(ctx context.Context, pkh string) (uint64, error) {
+	var id *uint64
+	if err := c.call(ctx, "account_id_by_pub_key_hash", []interface{}{pkh}, &id); err != nil {
+		return 0, err
+	}
+	if id == nil {
+		return 0, fmt.Errorf("No account with pubkey hash %s", pkh)
+	}
+	return *id, nil
+}
+
+// Nonces returns the committed nonce of each address, fetched with a single
+// batched JSON-RPC request.
+func (c *Client) Nonces(ctx context.Context, addresses []string) (map[string]uint64, error) {
+	nonces := make(map[string]uint64, len(addresses))
+	if len(addresses) == 0 {
+		return nonces, nil
+	}
+	reqs := make([]rpcRequest, len(addresses))
+	for i, address := range addresses {
+		reqs[i] = c.newRequest("account_info", []interface{}{address})
+	}
+	res, err := c.callBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range res {
+		if r.Error != nil {
+			return nil, fmt.Errorf("%s: %w", addresses[i], r.Error)
+		}
+		var state AccountState
+		if err := json.Unmarshal(r.Result, &state); err != nil {
+			return nil, fmt.Errorf("%s: %w", addresses[i], err)
+		}
+		nonces[addresses[i]] = state.Committed.Nonce
+	}
+	return nonces, nil
+}
+
+// PrevalidateTransfer checks a Transfer against the current state of its
+// sender before relaying it: tx.Validate must pass, tx.AccountId must be the
+// account of tx.From and tx.Nonce must not be below the committed nonce. A
+// nonce above it is accepted, since it may follow transfers still pending.
+func (c *Client) PrevalidateTransfer(ctx context.Context, tx *Tx) error {
+	if tx.Type != TxTypeTransfer {
+		return fmt.Errorf("Expected a %s, got %q", TxTypeTransfer, tx.Type)
+	}
+	if err := tx.Validate(); err != nil {
+		return err
+	}
+	state, err := c.AccountInfo(ctx, tx.From)
+	if err != nil {
+		return err
+	}
+	if state.Id == nil {
+		return fmt.Errorf("Account %s does not exist", tx.From)
+	}
+	if *state.Id != tx.AccountId {
+		return fmt.Errorf("Account %d is not owned by %s, whose account is %d", tx.AccountId, tx.From, *state.Id)
+	}
+	if tx.Nonce < state.Committed.Nonce {
+		return fmt.Errorf("Stale nonce %d: account %d is at nonce %d", tx.Nonce, tx.AccountId, state.Committed.Nonce)
+	}
+	return nil
+}
+
+// Account signs for one zkSync account: PrivateKey is its L2 key, which signs
+// the transactions, and EthSigner holds the key of the owning Ethereum
+// address, which signs their L1 approvals.
+//
+// An Account remembers the nonces it has signed transfers for, so a signing
+// service cannot sign two conflicting transfers with one nonce. It is safe
+// for concurrent use.
+type Account struct {
+	Address    string
+	PrivateKey *zkscrypto.PrivateKey
+	EthSigner  EthSigner
+
+	mu         sync.Mutex
+	seenNonces map[uint64]bool
+}
+
+// NewAccount returns the Account of the Ethereum address address.
+func NewAccount(address string, privateKey *zkscrypto.PrivateKey, ethSigner EthSigner) *Account {
+	return &Account{
+		Address:    address,
+		PrivateKey: privateKey,
+		EthSigner:  ethSigner,
+	}
+}
+
+// PubKeyHash returns the hash of the L2 public key in the "sync:" form used
+// by ChangePubKey and account_info.
+func (a *Account) PubKeyHash() (string, error) {
+	pub, err := a.PrivateKey.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	hash, err := pub.Hash()
+	if err != nil {
+		return "", err
+	}
+	return "sync:" + hash.HexString(), nil
+}
+
+// Activate sets the L2 key of a new account, the first thing an account must
+// do before it can send transactions. It looks up the account id and nonce,
+// builds a ChangePubKey to PubKeyHash with its fee in feeToken, authorizes it
+// with an ECDSA signature by EthSigner, signs it with PrivateKey and submits
+// it. The account must already exist, i.e. have received a deposit or
+// transfer.
+//
+// The Ethereum signature travels in EthAuthData, as zksync.js sends it, so
+// the returned Transaction has no separate EthSignature.
+func (a *Account) Activate(ctx context.Context, client *Client, feeToken uint64) (*Transaction, error) {
+	state, err := client.AccountInfo(ctx, a.Address)
+	if err != nil {
+		return nil, err
+	}
+	if state.Id == nil {
+		return nil, fmt.Errorf("Account %s does not exist yet: it needs a deposit or transfer first", a.Address)
+	}
+	pkh, err := a.PubKeyHash()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(state.Committed.PubKeyHash, pkh) {
+		return nil, fmt.Errorf("Account %s is already activated with this key", a.Address)
+	}
+	fee, err := client.txFee(ctx, string(TxTypeChangePubKey), a.Address, feeToken)
+	if err != nil {
+		return nil, err
+	}
+	tx := &Tx{
+		Type:       TxTypeChangePubKey,
+		AccountId:  *state.Id,
+		Account:    a.Address,
+		NewPkHash:  pkh,
+		FeeToken:   feeToken,
+		Fee:        NewBigInt(fee),
+		Nonce:      state.Committed.Nonce,
+		ValidUntil: DefaultValidUntil,
+	}
+	msg, err := ChangePubKeyEthMessage(tx)
+	if err != nil {
+		return nil, err
+	}
+	ethSig, err := SignEthMessage(msg, a.EthSigner)
+	if err != nil {
+		return nil, err
+	}
+	tx.EthAuthData = &ChangePubKeyAuthData{
+		Type:         ChangePubKeyECDSA,
+		EthSignature: ethSig.Signature,
+		BatchHash:    "0x" + strings.Repeat("00", 32),
+	}
+	sig, err := SignTx(tx, a.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	tx.AttachSignature(sig)
+	if _, err := client.SubmitTx(ctx, tx, nil); err != nil {
+		return nil, err
+	}
+	return &Transaction{Tx: *tx}, nil
+}
+
+// FillAccountId sets tx.AccountId to the id of the account registered for
+// PubKeyHash, looked up with AccountIdForPubKeyHash.
+func (a *Account) FillAccountId(ctx context.Context, client *Client, tx *Tx) error {
+	pkh, err := a.PubKeyHash()
+	if err != nil {
+		return err
+	}
+	id, err := client.AccountIdForPubKeyHash(ctx, pkh)
+	if err != nil {
+		return err
+	}
+	tx.AccountId = id
+	return nil
+}
+
+// SignTransferFull signs a Transfer with both keys it needs: PrivateKey
+// signs the serialized tx and ethSigner signs the BuildTransferEthMessage
+// text with the token's symbol and decimals. A nil ethSigner means the
+// Account's own EthSigner.
+func (a *Account) SignTransferFull(tx *Tx, ethSigner EthSigner, symbol string, decimals int) (*Transaction, error) {
+	if ethSigner == nil {
+		ethSigner = a.EthSigner
+	}
+	msg, err := BuildTransferEthMessage(tx, symbol, decimals)
+	if err != nil {
+		return nil, err
+	}
+	ethSig, err := SignEthMessage([]byte(msg), ethSigner)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := a.SignTransfer(tx)
+	if err != nil {
+		return nil, err
+	}
+	return AssembleTransaction(tx, sig, ethSig)
+}
+
+// SignTransfer signs a Transfer with PrivateKey like SignTx, but refuses a
+// nonce it has already signed a transfer with during the life of the
+// Account. The nonce is only used up if signing succeeds.
+func (a *Account) SignTransfer(tx *Tx) (*Signature, error) {
+	if tx.Type != TxTypeTransfer {
+		return nil, fmt.Errorf("Expected a %s, got %q", TxTypeTransfer, tx.Type)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.seenNonces[tx.Nonce] {
+		return nil, fmt.Errorf("Nonce %d was already signed for account %s", tx.Nonce, a.Address)
+	}
+	sig, err := SignTx(tx, a.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if a.seenNonces == nil {
+		a.seenNonces = make(map[uint64]bool)
+	}
+	a.seenNonces[tx.Nonce] = true
+	return sig, nil
+}
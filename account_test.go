@@ -0,0 +1,408 @@
+package zinc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAccountInfo(t *testing.T) {
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if req.Method != "account_info" {
+			t.Errorf("method %q, want account_info", req.Method)
+		}
+		if len(req.Params) != 1 || string(req.Params[0]) != `"`+testFrom+`"` {
+			t.Errorf("params %s, want [%q]", req.Params, testFrom)
+		}
+		return json.RawMessage(`{
+			"address": "` + testFrom + `",
+			"id": 7,
+			"committed": {
+				"balances": {"ETH": "1500000000000000000", "USDC": "2500000"},
+				"nonce": 12,
+				"pubKeyHash": "` + testPubKeyHash + `"
+			},
+			"verified": {
+				"balances": {"ETH": "1000000000000000000"},
+				"nonce": 10,
+				"pubKeyHash": "sync:0000000000000000000000000000000000000000"
+			}
+		}`), nil
+	})
+	state, err := client.AccountInfo(context.Background(), testFrom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Address != testFrom {
+		t.Errorf("address %s, want %s", state.Address, testFrom)
+	}
+	if state.Id == nil || *state.Id != 7 {
+		t.Errorf("id %v, want 7", state.Id)
+	}
+	if state.Committed.Nonce != 12 || state.Verified.Nonce != 10 {
+		t.Errorf("nonces %d committed, %d verified, want 12 and 10", state.Committed.Nonce, state.Verified.Nonce)
+	}
+	if state.Committed.PubKeyHash != testPubKeyHash {
+		t.Errorf("pubKeyHash %s, want %s", state.Committed.PubKeyHash, testPubKeyHash)
+	}
+	for symbol, want := range map[string]string{"ETH": "1500000000000000000", "USDC": "2500000"} {
+		if got := state.Committed.Balances[symbol].String(); got != want {
+			t.Errorf("committed %s balance %s, want %s", symbol, got, want)
+		}
+	}
+	if got := state.Verified.Balances["ETH"].String(); got != "1000000000000000000" {
+		t.Errorf("verified ETH balance %s, want 1000000000000000000", got)
+	}
+}
+
+func TestAccountInfoNewAccount(t *testing.T) {
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		return json.RawMessage(`{
+			"address": "` + testTo + `",
+			"id": null,
+			"committed": {"balances": {}, "nonce": 0, "pubKeyHash": "sync:0000000000000000000000000000000000000000"},
+			"verified": {"balances": {}, "nonce": 0, "pubKeyHash": "sync:0000000000000000000000000000000000000000"}
+		}`), nil
+	})
+	state, err := client.AccountInfo(context.Background(), testTo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Id != nil {
+		t.Errorf("id %d, want nil for an account without a deposit", *state.Id)
+	}
+}
+
+func TestNonces(t *testing.T) {
+	addresses := []string{testFrom, testTo, "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"}
+	want := map[string]uint64{addresses[0]: 12, addresses[1]: 0, addresses[2]: 5}
+	var requests int32
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var reqs []testRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Errorf("decoding batch: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(reqs) != len(addresses) {
+			t.Errorf("%d requests in batch, want %d", len(reqs), len(addresses))
+		}
+		// Reply in reverse order: the client must match responses by id.
+		res := make([]testRPCResponse, len(reqs))
+		for i, req := range reqs {
+			if req.Method != "account_info" || len(req.Params) != 1 {
+				t.Errorf("request %d: %s %s, want account_info with one address", i, req.Method, req.Params)
+				continue
+			}
+			var address string
+			json.Unmarshal(req.Params[0], &address)
+			state := AccountState{Address: address}
+			state.Committed.Nonce = want[address]
+			res[len(reqs)-1-i] = testRPCResponse{JSONRPC: "2.0", Id: req.Id, Result: state}
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+	got, err := client.Nonces(context.Background(), addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("%d HTTP requests, want 1 batch", n)
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d nonces, want %d", len(got), len(want))
+	}
+	for address, nonce := range want {
+		if n, ok := got[address]; !ok || n != nonce {
+			t.Errorf("nonce of %s = %d, %v, want %d", address, n, ok, nonce)
+		}
+	}
+}
+
+func TestNoncesErrors(t *testing.T) {
+	// One failed call fails the lookup and names the address.
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var reqs []testRPCRequest
+		json.NewDecoder(r.Body).Decode(&reqs)
+		res := make([]testRPCResponse, len(reqs))
+		for i, req := range reqs {
+			res[i] = testRPCResponse{JSONRPC: "2.0", Id: req.Id, Result: AccountState{}}
+		}
+		res[1].Result = nil
+		res[1].Error = &RPCError{Code: -32602, Message: "Invalid address"}
+		json.NewEncoder(w).Encode(res)
+	})
+	_, err := client.Nonces(context.Background(), []string{testFrom, "0xbad"})
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || !strings.Contains(err.Error(), "0xbad") {
+		t.Errorf("got %v, want an *RPCError naming 0xbad", err)
+	}
+
+	// A reply missing a response is an error, not a zero nonce.
+	client = newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var reqs []testRPCRequest
+		json.NewDecoder(r.Body).Decode(&reqs)
+		json.NewEncoder(w).Encode([]testRPCResponse{{JSONRPC: "2.0", Id: reqs[0].Id, Result: AccountState{}}})
+	})
+	if _, err := client.Nonces(context.Background(), []string{testFrom, testTo}); err == nil {
+		t.Error("missing batch response accepted")
+	}
+}
+
+func TestNoncesEmpty(t *testing.T) {
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for no addresses")
+	})
+	got, err := client.Nonces(context.Background(), nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("got %v, %v, want an empty map", got, err)
+	}
+}
+
+// testAccount returns the Account of the first Hardhat address with the
+// zero-seed L2 key.
+func testAccount(t *testing.T) *Account {
+	t.Helper()
+	key, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAccount(hardhatAddress, key, hardhatSigner(t, 0))
+}
+
+func TestAccountActivate(t *testing.T) {
+	account := testAccount(t)
+	pkh, err := account.PubKeyHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var submitted Tx
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		switch req.Method {
+		case "account_info":
+			return json.RawMessage(`{
+				"address": "` + hardhatAddress + `",
+				"id": 7,
+				"committed": {"balances": {"ETH": "1000000000000000000"}, "nonce": 3, "pubKeyHash": "sync:0000000000000000000000000000000000000000"},
+				"verified": {"balances": {}, "nonce": 0, "pubKeyHash": "sync:0000000000000000000000000000000000000000"}
+			}`), nil
+		case "get_tx_fee":
+			if len(req.Params) != 3 || string(req.Params[0]) != `{"ChangePubKey":"ECDSA"}` || string(req.Params[2]) != "0" {
+				t.Errorf("get_tx_fee params %s, want an ECDSA ChangePubKey fee in token 0", req.Params)
+			}
+			return json.RawMessage(`{"totalFee":"37500000000000"}`), nil
+		case "tx_submit":
+			if len(req.Params) != 2 || string(req.Params[1]) != "null" {
+				t.Errorf("tx_submit params %s, want the tx and null", req.Params)
+				return nil, &RPCError{Code: -32602, Message: "Invalid params"}
+			}
+			if err := json.Unmarshal(req.Params[0], &submitted); err != nil {
+				t.Errorf("decoding tx: %v", err)
+			}
+			return "sync-tx:abcd", nil
+		}
+		t.Errorf("unexpected method %s", req.Method)
+		return nil, &RPCError{Code: -32601, Message: "Method not found"}
+	})
+	txn, err := account.Activate(context.Background(), client, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameTx(t, &submitted, &txn.Tx)
+	tx := txn.Tx
+	if tx.Type != TxTypeChangePubKey || tx.AccountId != 7 || tx.Nonce != 3 || tx.Account != hardhatAddress {
+		t.Errorf("got %s of account %d (%s) with nonce %d", tx.Type, tx.AccountId, tx.Account, tx.Nonce)
+	}
+	if tx.NewPkHash != pkh || tx.Fee.String() != "37500000000000" {
+		t.Errorf("newPkHash %s, fee %s, want %s and 37500000000000", tx.NewPkHash, tx.Fee, pkh)
+	}
+	if tx.Signature.PubKey == "" || tx.Signature.Signature == "" {
+		t.Error("ChangePubKey has no L2 signature")
+	}
+	if tx.EthAuthData == nil || tx.EthAuthData.Type != ChangePubKeyECDSA {
+		t.Fatalf("ethAuthData %+v, want ECDSA", tx.EthAuthData)
+	}
+	msg, err := ChangePubKeyEthMessage(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := recoverPersonalSigner(t, msg, tx.EthAuthData.EthSignature); signer != hardhatAddress {
+		t.Errorf("ECDSA auth signed by %s, want %s", signer, hardhatAddress)
+	}
+}
+
+func TestAccountActivateErrors(t *testing.T) {
+	account := testAccount(t)
+	pkh, err := account.PubKeyHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"no account":       `{"address":"` + hardhatAddress + `","id":null,"committed":{"nonce":0,"pubKeyHash":""},"verified":{}}`,
+		"already this key": `{"address":"` + hardhatAddress + `","id":7,"committed":{"nonce":3,"pubKeyHash":"` + pkh + `"},"verified":{}}`,
+	}
+	for name, state := range tests {
+		client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+			if req.Method != "account_info" {
+				t.Errorf("%s: unexpected %s", name, req.Method)
+			}
+			return json.RawMessage(state), nil
+		})
+		if _, err := account.Activate(context.Background(), client, 0); err == nil {
+			t.Errorf("%s: activated", name)
+		}
+	}
+}
+
+func TestAccountSignTransferFull(t *testing.T) {
+	account := testAccount(t)
+	tx := testTransfer()
+	tx.From = hardhatAddress
+	txn, err := account.SignTransferFull(tx, nil, "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Tx.Signature.PubKey == "" || txn.Tx.Signature.Signature == "" {
+		t.Errorf("L2 signature not set: %+v", txn.Tx.Signature)
+	}
+	if txn.EthSignature.Type != "EthereumSignature" || txn.EthSignature.Signature == "" {
+		t.Errorf("L1 signature not set: %+v", txn.EthSignature)
+	}
+	ok, err := VerifyEthSignature(txn, "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("L1 signature does not verify against the sender")
+	}
+
+	// Another EthSigner signs, so the signature is not the sender's.
+	other, err := testAccount(t).SignTransferFull(tx, hardhatSigner(t, 1), "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyEthSignature(other, "ETH", 18); err != nil || ok {
+		t.Errorf("signature by another key: got %v, %v, want false", ok, err)
+	}
+
+	if _, err := account.SignTransferFull(testChangePubKey(), nil, "ETH", 18); err == nil {
+		t.Error("ChangePubKey signed as a Transfer")
+	}
+}
+
+func TestAccountSignTransferNonceReuse(t *testing.T) {
+	account := testAccount(t)
+	tx := testTransfer()
+	if _, err := account.SignTransfer(tx); err != nil {
+		t.Fatal(err)
+	}
+	// Same nonce, different recipient: signing it would create a conflict.
+	conflict := testTransfer()
+	conflict.To = testFrom
+	if _, err := account.SignTransfer(conflict); err == nil {
+		t.Error("second transfer with the same nonce signed")
+	}
+	next := testTransfer()
+	next.Nonce++
+	if _, err := account.SignTransfer(next); err != nil {
+		t.Errorf("next nonce: %v", err)
+	}
+	// SignTransferFull shares the set.
+	next.From = hardhatAddress
+	if _, err := account.SignTransferFull(next, nil, "ETH", 18); err == nil {
+		t.Error("SignTransferFull reused a nonce")
+	}
+	// Another Account has its own set.
+	if _, err := testAccount(t).SignTransfer(tx); err != nil {
+		t.Errorf("fresh account: %v", err)
+	}
+}
+
+func TestAccountIdForPubKeyHash(t *testing.T) {
+	account := testAccount(t)
+	pkh, err := account.PubKeyHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if req.Method != "account_id_by_pub_key_hash" {
+			t.Errorf("method %q, want account_id_by_pub_key_hash", req.Method)
+		}
+		if len(req.Params) != 1 {
+			t.Errorf("%d params, want 1", len(req.Params))
+			return nil, &RPCError{Code: -32602, Message: "Invalid params"}
+		}
+		var got string
+		if err := json.Unmarshal(req.Params[0], &got); err != nil {
+			t.Errorf("decoding pubkey hash: %v", err)
+		}
+		if got == pkh {
+			return 42, nil
+		}
+		return json.RawMessage("null"), nil
+	})
+
+	id, err := client.AccountIdForPubKeyHash(context.Background(), pkh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Errorf("id %d, want 42", id)
+	}
+	if _, err := client.AccountIdForPubKeyHash(context.Background(), testPubKeyHash); err == nil {
+		t.Error("unknown pubkey hash resolved")
+	}
+
+	tx := testTransfer()
+	if err := account.FillAccountId(context.Background(), client, tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.AccountId != 42 {
+		t.Errorf("filled account id %d, want 42", tx.AccountId)
+	}
+}
+
+func TestPrevalidateTransfer(t *testing.T) {
+	// The node has testFrom as account 7 at nonce 12, which testTransfer uses.
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if req.Method != "account_info" {
+			t.Errorf("method %q, want account_info", req.Method)
+		}
+		if len(req.Params) == 1 && string(req.Params[0]) == `"`+testFrom+`"` {
+			return json.RawMessage(`{"address": "` + testFrom + `", "id": 7, "committed": {"nonce": 12}, "verified": {"nonce": 10}}`), nil
+		}
+		return json.RawMessage(`{"address": "` + testTo + `", "id": null, "committed": {"nonce": 0}, "verified": {"nonce": 0}}`), nil
+	})
+	ctx := context.Background()
+
+	if err := client.PrevalidateTransfer(ctx, testTransfer()); err != nil {
+		t.Errorf("matching state: %v", err)
+	}
+	ahead := testTransfer()
+	ahead.Nonce = 13
+	if err := client.PrevalidateTransfer(ctx, ahead); err != nil {
+		t.Errorf("nonce ahead of committed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		edit func(tx *Tx)
+	}{
+		{"stale nonce", func(tx *Tx) { tx.Nonce = 11 }},
+		{"other account", func(tx *Tx) { tx.AccountId = 8 }},
+		{"unknown sender", func(tx *Tx) { tx.From = testTo }},
+		{"invalid", func(tx *Tx) { tx.To = "0x0000000000000000000000000000000000000000" }},
+	}
+	for _, tt := range tests {
+		tx := testTransfer()
+		tt.edit(tx)
+		if err := client.PrevalidateTransfer(ctx, tx); err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+}
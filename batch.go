@@ -0,0 +1,204 @@
+package zinc
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"sync"
+
+	zkscrypto "github.com/zksync-sdk/zksync-sdk-go"
+)
+
+// SerializeBatchParallel serializes txs on up to workers goroutines and returns
+// the results in input order. It stops handing out work after the first
+// failure and returns that error.
+func SerializeBatchParallel(txs []*Tx, workers int) ([][]byte, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	out := make([][]byte, len(txs))
+	jobs := make(chan int)
+	failed := make(chan struct{})
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				ser, err := SerializeTx(txs[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("Transaction %d: %w", i, err)
+						close(failed)
+					})
+					continue
+				}
+				out[i] = ser
+			}
+		}()
+	}
+feed:
+	for i := range txs {
+		select {
+		case jobs <- i:
+		case <-failed:
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// BatchTotalFee returns the sum of the fees of txs as they travel packed. A
+// fee that has no packed encoding, such as a negative one, is an error
+// wrapping ErrAmountNotPackable.
+func BatchTotalFee(txs []*Tx) (*big.Int, error) {
+	total := new(big.Int)
+	for i, tx := range txs {
+		packed, err := packFee(tx.Fee.Value())
+		if err != nil {
+			return nil, fmt.Errorf("Transaction %d: %w", i, err)
+		}
+		fee, err := decodePackedFee(packed)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(total, fee)
+	}
+	return total, nil
+}
+
+// senderAccountId returns the id of the account whose nonce tx consumes.
+func senderAccountId(tx *Tx) uint64 {
+	if tx.Type == TxTypeForcedExit {
+		return tx.InitiatorAccountId
+	}
+	return tx.AccountId
+}
+
+// Conflicts reports whether a and b spend the same nonce of the same account,
+// so at most one of them can be executed.
+func Conflicts(a, b *Tx) bool {
+	return senderAccountId(a) == senderAccountId(b) && a.Nonce == b.Nonce
+}
+
+// DeduplicateByNonce drops every transaction that conflicts with a later one,
+// keeping the last transaction for each (account, nonce) pair in its original
+// position.
+func DeduplicateByNonce(txs []*Tx) []*Tx {
+	type key struct{ account, nonce uint64 }
+	last := make(map[key]int, len(txs))
+	for i, tx := range txs {
+		last[key{senderAccountId(tx), tx.Nonce}] = i
+	}
+	res := make([]*Tx, 0, len(last))
+	for i, tx := range txs {
+		if last[key{senderAccountId(tx), tx.Nonce}] == i {
+			res = append(res, tx)
+		}
+	}
+	return res
+}
+
+// Batch is a group of transactions executed atomically, authorized by a
+// single Ethereum signature over the whole batch.
+type Batch struct {
+	Txs          []*Tx
+	EthSignature *EthereumSignature
+}
+
+// Add appends tx to the batch.
+func (b *Batch) Add(tx *Tx) {
+	b.Txs = append(b.Txs, tx)
+}
+
+// Serialize returns the concatenated serializations of the transactions.
+func (b *Batch) Serialize() ([]byte, error) {
+	if len(b.Txs) == 0 {
+		return nil, fmt.Errorf("Batch is empty")
+	}
+	var res []byte
+	for i, tx := range b.Txs {
+		ser, err := SerializeTx(tx)
+		if err != nil {
+			return nil, fmt.Errorf("Transaction %d: %w", i, err)
+		}
+		res = append(res, ser...)
+	}
+	return res, nil
+}
+
+// checkNonces reports an error unless the transactions of each account use
+// consecutive nonces in batch order.
+func (b *Batch) checkNonces() error {
+	next := make(map[uint64]uint64)
+	for i, tx := range b.Txs {
+		account := senderAccountId(tx)
+		if want, ok := next[account]; ok && tx.Nonce != want {
+			return fmt.Errorf("Transaction %d: nonce %d of account %d, expected %d", i, tx.Nonce, account, want)
+		}
+		next[account] = tx.Nonce + 1
+	}
+	return nil
+}
+
+// SignBatch signs every transaction with privateKey and then signs the batch
+// with ethSigner. The Ethereum signature is a personal_sign over the SHA-256
+// of Serialize, as zksync.js does for batches.
+func (b *Batch) SignBatch(privateKey *zkscrypto.PrivateKey, ethSigner EthSigner) error {
+	if len(b.Txs) == 0 {
+		return fmt.Errorf("Batch is empty")
+	}
+	if err := b.checkNonces(); err != nil {
+		return err
+	}
+	for i, tx := range b.Txs {
+		sig, err := SignTx(tx, privateKey)
+		if err != nil {
+			return fmt.Errorf("Transaction %d: %w", i, err)
+		}
+		tx.AttachSignature(sig)
+	}
+	ser, err := b.Serialize()
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(ser)
+	ethSig, err := SignEthMessage(hash[:], ethSigner)
+	if err != nil {
+		return err
+	}
+	b.EthSignature = ethSig
+	return nil
+}
+
+type batchTx struct {
+	Tx        *Tx                `json:"tx"`
+	Signature *EthereumSignature `json:"signature"`
+}
+
+// SubmitBatch submits a signed batch with submit_txs_batch and returns the
+// hashes the server assigned to its transactions.
+func (c *Client) SubmitBatch(ctx context.Context, batch *Batch) ([]string, error) {
+	if len(batch.Txs) == 0 {
+		return nil, fmt.Errorf("Batch is empty")
+	}
+	txs := make([]batchTx, len(batch.Txs))
+	for i, tx := range batch.Txs {
+		txs[i] = batchTx{Tx: tx}
+	}
+	var hashes []string
+	if err := c.call(ctx, "submit_txs_batch", []interface{}{txs, batch.EthSignature}, &hashes); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
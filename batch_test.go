@@ -0,0 +1,362 @@
+package zinc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBatch returns n transfers from one account with consecutive nonces.
+func testBatch(n int) []*Tx {
+	txs := make([]*Tx, n)
+	for i := range txs {
+		tx := testTransfer()
+		tx.Nonce = uint64(i)
+		txs[i] = tx
+	}
+	return txs
+}
+
+// BenchmarkSerializeBatch measures SerializeBatchParallel over batch sizes a
+// relayer sees. The txs/s metric extrapolates to other sizes.
+func BenchmarkSerializeBatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			txs := testBatch(n)
+			workers := runtime.GOMAXPROCS(0)
+			b.ReportAllocs()
+			b.ResetTimer()
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				if _, err := SerializeBatchParallel(txs, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(n*b.N)/time.Since(start).Seconds(), "txs/s")
+		})
+	}
+}
+
+func TestBatchSerialize(t *testing.T) {
+	var batch Batch
+	if _, err := batch.Serialize(); err == nil {
+		t.Error("empty batch serialized without an error")
+	}
+	var want []byte
+	for _, tx := range testBatch(3) {
+		batch.Add(tx)
+		ser, err := SerializeTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, ser...)
+	}
+	got, err := batch.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %x\nwant %x", got, want)
+	}
+}
+
+func TestSignBatch(t *testing.T) {
+	key, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := Batch{Txs: testBatch(3)}
+	signer := &mockEthSigner{sig: cannedEthSignature}
+	if err := batch.SignBatch(key, signer); err != nil {
+		t.Fatal(err)
+	}
+	for i, tx := range batch.Txs {
+		if tx.Signature.Signature == "" {
+			t.Errorf("transaction %d has no L2 signature", i)
+		}
+	}
+	ser, err := batch.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256(ser)
+	if want := personalMessageHash(hash[:]); !bytes.Equal(signer.hash, want) {
+		t.Errorf("signer got hash %x, want the personal_sign hash %x of the batch SHA-256", signer.hash, want)
+	}
+	if batch.EthSignature == nil {
+		t.Error("batch has no Ethereum signature")
+	}
+}
+
+func TestSignBatchNonces(t *testing.T) {
+	key, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Batch{}).SignBatch(key, &mockEthSigner{sig: cannedEthSignature}); err == nil {
+		t.Error("empty batch signed")
+	}
+	tests := map[string][]uint64{
+		"gap":        {0, 2},
+		"repeated":   {0, 0},
+		"decreasing": {1, 0},
+	}
+	for name, nonces := range tests {
+		batch := Batch{Txs: testBatch(len(nonces))}
+		for i, nonce := range nonces {
+			batch.Txs[i].Nonce = nonce
+		}
+		signer := &mockEthSigner{sig: cannedEthSignature}
+		if err := batch.SignBatch(key, signer); err == nil {
+			t.Errorf("%s nonces %v signed", name, nonces)
+		}
+		if signer.hash != nil {
+			t.Errorf("%s nonces: batch sent to the Ethereum signer", name)
+		}
+	}
+	// Different accounts keep their own nonce sequences.
+	other := testTransfer()
+	other.AccountId = 8
+	other.Nonce = 40
+	batch := Batch{Txs: append(testBatch(2), other)}
+	if err := batch.SignBatch(key, &mockEthSigner{sig: cannedEthSignature}); err != nil {
+		t.Errorf("two accounts: %v", err)
+	}
+}
+
+func TestSubmitBatch(t *testing.T) {
+	batch := Batch{
+		Txs:          testBatch(2),
+		EthSignature: &EthereumSignature{Type: "EthereumSignature", Signature: "0x1234"},
+	}
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if req.Method != "submit_txs_batch" {
+			t.Errorf("method %q, want submit_txs_batch", req.Method)
+		}
+		if len(req.Params) != 2 {
+			t.Errorf("%d params, want 2", len(req.Params))
+			return nil, &RPCError{Code: -32602, Message: "Invalid params"}
+		}
+		var txs []struct {
+			Tx        Tx               `json:"tx"`
+			Signature *json.RawMessage `json:"signature"`
+		}
+		if err := json.Unmarshal(req.Params[0], &txs); err != nil {
+			t.Errorf("decoding txs: %v", err)
+		}
+		if len(txs) != len(batch.Txs) {
+			t.Errorf("%d txs, want %d", len(txs), len(batch.Txs))
+		}
+		for i := range txs {
+			assertSameTx(t, &txs[i].Tx, batch.Txs[i])
+			if txs[i].Signature != nil {
+				t.Errorf("tx %d has signature %s, want null", i, *txs[i].Signature)
+			}
+		}
+		var ethSig EthereumSignature
+		if err := json.Unmarshal(req.Params[1], &ethSig); err != nil || ethSig != *batch.EthSignature {
+			t.Errorf("batch signature %s, want %+v", req.Params[1], *batch.EthSignature)
+		}
+		return []string{"sync-tx:01", "sync-tx:02"}, nil
+	})
+	hashes, err := client.SubmitBatch(context.Background(), &batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 2 || hashes[0] != "sync-tx:01" || hashes[1] != "sync-tx:02" {
+		t.Errorf("hashes %v, want [sync-tx:01 sync-tx:02]", hashes)
+	}
+}
+
+func TestSubmitBatchEmpty(t *testing.T) {
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for an empty batch")
+	})
+	if _, err := client.SubmitBatch(context.Background(), &Batch{}); err == nil {
+		t.Error("empty batch submitted")
+	}
+}
+
+func TestSerializeBatchParallel(t *testing.T) {
+	txs := testBatch(100)
+	for _, workers := range []int{0, 1, 4, 200} {
+		got, err := SerializeBatchParallel(txs, workers)
+		if err != nil {
+			t.Fatalf("%d workers: %v", workers, err)
+		}
+		if len(got) != len(txs) {
+			t.Fatalf("%d workers: %d results, want %d", workers, len(got), len(txs))
+		}
+		for i, tx := range txs {
+			want, err := SerializeTx(tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got[i], want) {
+				t.Errorf("%d workers: result %d is not the serialization of tx %d", workers, i, i)
+			}
+		}
+	}
+}
+
+func TestSerializeBatchParallelError(t *testing.T) {
+	txs := testBatch(50)
+	txs[37].To = "0x1234"
+	got, err := SerializeBatchParallel(txs, 4)
+	if err == nil {
+		t.Fatal("batch with a bad recipient serialized")
+	}
+	if got != nil {
+		t.Errorf("got %d results with the error", len(got))
+	}
+	if want := "Transaction 37:"; !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("error %q does not name the failing transaction", err)
+	}
+}
+
+func TestVerifyBatchEthSignature(t *testing.T) {
+	key, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Batch{Txs: testBatch(3)}
+	if err := b.SignBatch(key, hardhatSigner(t, 0)); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(b.EthSignature.Signature, "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifyBatchEthSignature(b.Txs, sig, hardhatAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("batch signature does not verify")
+	}
+
+	if ok, err := VerifyBatchEthSignature(b.Txs, sig, "0x70997970c51812dc3a010c7d01b50e0d17dc79c8"); err != nil || ok {
+		t.Errorf("other signer: got %v, %v, want false", ok, err)
+	}
+	tampered := testBatch(3)
+	tampered[1].Amount = NewBigInt(big.NewInt(1))
+	if ok, err := VerifyBatchEthSignature(tampered, sig, hardhatAddress); err != nil || ok {
+		t.Errorf("tampered batch: got %v, %v, want false", ok, err)
+	}
+	if ok, err := VerifyBatchEthSignature(b.Txs[:2], sig, hardhatAddress); err != nil || ok {
+		t.Errorf("truncated batch: got %v, %v, want false", ok, err)
+	}
+	if _, err := VerifyBatchEthSignature(b.Txs, sig[:64], hardhatAddress); err == nil {
+		t.Error("64-byte signature accepted")
+	}
+}
+
+func TestBatchTotalFee(t *testing.T) {
+	txs := testBatch(3)
+	txs[1].Fee = NewBigInt(big.NewInt(1))
+	txs[2].Fee = NewBigInt(nil)
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	withdraw.Fee = NewBigInt(big.NewInt(2000000000000000))
+	txs = append(txs, testChangePubKey(), withdraw)
+	// 37500000000000 + 1 + 0 + 37500000000000 + 2000000000000000
+	want := big.NewInt(2075000000000001)
+	got, err := BatchTotalFee(txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(want) != 0 {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if got, err := BatchTotalFee(nil); err != nil || got.Sign() != 0 {
+		t.Errorf("empty batch: got %v, %v, want 0", got, err)
+	}
+	for _, fee := range []int64{-1, 123456789} {
+		bad := testBatch(2)
+		bad[1].Fee = NewBigInt(big.NewInt(fee))
+		if _, err := BatchTotalFee(bad); !errors.Is(err, ErrAmountNotPackable) {
+			t.Errorf("fee %d: got %v, want ErrAmountNotPackable", fee, err)
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	a := testTransfer()
+	sameNonce := testTransfer()
+	sameNonce.To = testFrom
+	otherNonce := testTransfer()
+	otherNonce.Nonce++
+	otherAccount := testTransfer()
+	otherAccount.AccountId++
+	// A ForcedExit spends the nonce of its initiator.
+	exit := testForcedExit()
+	exit.InitiatorAccountId = a.AccountId
+	exit.Nonce = a.Nonce
+
+	tests := []struct {
+		name string
+		b    *Tx
+		want bool
+	}{
+		{"same account and nonce", sameNonce, true},
+		{"itself", a, true},
+		{"other nonce", otherNonce, false},
+		{"other account", otherAccount, false},
+		{"forced exit by the same account", exit, true},
+	}
+	for _, tt := range tests {
+		if got := Conflicts(a, tt.b); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if got := Conflicts(tt.b, a); got != tt.want {
+			t.Errorf("%s, swapped: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeduplicateByNonce(t *testing.T) {
+	tx := func(account, nonce uint64, to string) *Tx {
+		tx := testTransfer()
+		tx.AccountId, tx.Nonce, tx.To = account, nonce, to
+		return tx
+	}
+	a0 := tx(1, 0, testTo)
+	b0 := tx(2, 0, testTo)
+	a1 := tx(1, 1, testTo)
+	a0Again := tx(1, 0, testFrom)
+	b0Again := tx(2, 0, testFrom)
+	a2 := tx(1, 2, testTo)
+
+	got := DeduplicateByNonce([]*Tx{a0, b0, a1, a0Again, b0Again, a2})
+	// The last of each (account, nonce) pair survives, and the survivors
+	// keep their input order.
+	want := []*Tx{a1, a0Again, b0Again, a2}
+	if len(got) != len(want) {
+		t.Fatalf("%d transactions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("transaction %d: got account %d nonce %d to %s, want account %d nonce %d to %s",
+				i, got[i].AccountId, got[i].Nonce, got[i].To, want[i].AccountId, want[i].Nonce, want[i].To)
+		}
+	}
+
+	distinct := []*Tx{a0, b0, a1}
+	if got := DeduplicateByNonce(distinct); len(got) != 3 || got[0] != a0 || got[1] != b0 || got[2] != a1 {
+		t.Error("transactions without conflicts were changed")
+	}
+	if got := DeduplicateByNonce(nil); len(got) != 0 {
+		t.Errorf("empty input gave %d transactions", len(got))
+	}
+}
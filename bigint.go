@@ -0,0 +1,62 @@
+package zinc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// BigInt is an integer amount that is written in JSON as a decimal string,
+// like the "amount" and "fee" fields of the input. A nil Int reads as zero.
+type BigInt struct {
+	*big.Int
+}
+
+// NewBigInt wraps x.
+func NewBigInt(x *big.Int) BigInt {
+	return BigInt{x}
+}
+
+// Value returns the wrapped integer, or zero if it is unset.
+func (b BigInt) Value() *big.Int {
+	if b.Int == nil {
+		return new(big.Int)
+	}
+	return b.Int
+}
+
+func (b BigInt) String() string {
+	return b.Value().String()
+}
+
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	value, err := parseDecimal(s)
+	if err != nil {
+		return err
+	}
+	b.Int = value
+	return nil
+}
+
+// parseDecimal parses a non-negative base-10 integer. Unlike
+// big.Int.SetString it rejects signs and anything but digits.
+func parseDecimal(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("Invalid decimal: empty string")
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("Invalid decimal: %q", s)
+		}
+	}
+	value, _ := new(big.Int).SetString(s, 10)
+	return value, nil
+}
@@ -0,0 +1,21 @@
+package zinc
+
+import "testing"
+
+func TestParseDecimal(t *testing.T) {
+	for _, s := range []string{"0", "37500000000000", "123456789012345678901234567890"} {
+		got, err := parseDecimal(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if got.String() != s {
+			t.Errorf("%q parsed as %s", s, got)
+		}
+	}
+	for _, s := range []string{"", "-1", "+1", "1e3", "0x10", " 1", "1 ", "1.5", "1_000"} {
+		if got, err := parseDecimal(s); err == nil {
+			t.Errorf("%q accepted as %s", s, got)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package zinc
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// TransferBuilder assembles a Transfer with chained setters:
+//
+//	tx, err := new(TransferBuilder).From(from).To(to).Amount(amount).Fee(fee).Nonce(n).Build()
+//
+// From and To are required. ValidUntil defaults to DefaultValidUntil.
+type TransferBuilder struct {
+	tx         Tx
+	amount     *big.Int
+	fee        *big.Int
+	validUntil *uint64
+}
+
+func (b *TransferBuilder) AccountId(id uint64) *TransferBuilder {
+	b.tx.AccountId = id
+	return b
+}
+
+func (b *TransferBuilder) From(address string) *TransferBuilder {
+	b.tx.From = address
+	return b
+}
+
+func (b *TransferBuilder) To(address string) *TransferBuilder {
+	b.tx.To = address
+	return b
+}
+
+func (b *TransferBuilder) Token(id uint64) *TransferBuilder {
+	b.tx.Token = id
+	return b
+}
+
+// Amount sets the amount in base units. Build rounds it down to the closest
+// packable amount.
+func (b *TransferBuilder) Amount(amount *big.Int) *TransferBuilder {
+	b.amount = amount
+	return b
+}
+
+// Fee sets the fee in base units. Build rounds it down to the closest
+// packable fee.
+func (b *TransferBuilder) Fee(fee *big.Int) *TransferBuilder {
+	b.fee = fee
+	return b
+}
+
+func (b *TransferBuilder) Nonce(nonce uint64) *TransferBuilder {
+	b.tx.Nonce = nonce
+	return b
+}
+
+func (b *TransferBuilder) ValidFrom(ts uint64) *TransferBuilder {
+	b.tx.ValidFrom = ts
+	return b
+}
+
+func (b *TransferBuilder) ValidUntil(ts uint64) *TransferBuilder {
+	b.validUntil = &ts
+	return b
+}
+
+// Build returns a new Transfer from the values set so far. Each call returns
+// an independent Tx.
+func (b *TransferBuilder) Build() (*Tx, error) {
+	if b.tx.From == "" {
+		return nil, fmt.Errorf("Missing required field: from")
+	}
+	if b.tx.To == "" {
+		return nil, fmt.Errorf("Missing required field: to")
+	}
+	tx := b.tx
+	tx.Type = TxTypeTransfer
+	tx.ValidUntil = DefaultValidUntil
+	if b.validUntil != nil {
+		tx.ValidUntil = *b.validUntil
+	}
+	amount := new(big.Int)
+	if b.amount != nil {
+		closest, _, err := ClosestPackableTransactionAmount(b.amount)
+		if err != nil {
+			return nil, err
+		}
+		amount = closest
+	}
+	tx.Amount = NewBigInt(amount)
+	fee := new(big.Int)
+	if b.fee != nil {
+		closest, _, err := ClosestPackableTransactionFee(b.fee)
+		if err != nil {
+			return nil, err
+		}
+		fee = closest
+	}
+	tx.Fee = NewBigInt(fee)
+	return &tx, nil
+}
@@ -0,0 +1,111 @@
+package zinc
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestTransferBuilder(t *testing.T) {
+	tx, err := new(TransferBuilder).
+		AccountId(7).
+		From(testFrom).
+		To(testTo).
+		Token(3).
+		Amount(big.NewInt(1500000000000000000)).
+		Fee(big.NewInt(37500000000000)).
+		Nonce(12).
+		ValidUntil(4294967295).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameTx(t, tx, testTransfer())
+}
+
+func TestTransferBuilderDefaults(t *testing.T) {
+	tx, err := new(TransferBuilder).From(testFrom).To(testTo).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type != TxTypeTransfer {
+		t.Errorf("type %q, want %q", tx.Type, TxTypeTransfer)
+	}
+	if tx.ValidUntil != DefaultValidUntil {
+		t.Errorf("validUntil %d, want DefaultValidUntil", tx.ValidUntil)
+	}
+	if tx.Amount.Sign() != 0 || tx.Fee.Sign() != 0 {
+		t.Errorf("amount %s, fee %s, want 0 and 0", tx.Amount, tx.Fee)
+	}
+}
+
+func TestTransferBuilderMissingField(t *testing.T) {
+	tests := []struct {
+		name  string
+		build *TransferBuilder
+		field string
+	}{
+		{"no from", new(TransferBuilder).To(testTo), "from"},
+		{"no to", new(TransferBuilder).From(testFrom), "to"},
+		{"nothing", new(TransferBuilder), "from"},
+	}
+	for _, tt := range tests {
+		_, err := tt.build.Build()
+		if err == nil || !strings.HasSuffix(err.Error(), ": "+tt.field) {
+			t.Errorf("%s: got %v, want an error naming %s", tt.name, err, tt.field)
+		}
+	}
+}
+
+func TestTransferBuilderRounding(t *testing.T) {
+	tx, err := new(TransferBuilder).
+		From(testFrom).
+		To(testTo).
+		// One more than the largest 35-bit mantissa.
+		Amount(big.NewInt(34359738368)).
+		// One more than the largest 11-bit mantissa.
+		Fee(big.NewInt(2048)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tx.Amount.String(); got != "34359738360" {
+		t.Errorf("amount %s, want 34359738360", got)
+	}
+	if got := tx.Fee.String(); got != "2040" {
+		t.Errorf("fee %s, want 2040", got)
+	}
+	if _, err := SerializeTransfer(tx); err != nil {
+		t.Errorf("rounded transfer does not serialize: %v", err)
+	}
+}
+
+func TestTransferBuilderIndependentBuilds(t *testing.T) {
+	amount := big.NewInt(1000)
+	b := new(TransferBuilder).From(testFrom).To(testTo).Amount(amount).Nonce(1)
+	first, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatal("Build returned the same *Tx twice")
+	}
+	first.Nonce = 99
+	first.To = testFrom
+	first.Amount.SetInt64(5)
+	amount.SetInt64(7)
+	if second.Nonce != 1 || second.To != testTo || second.Amount.Int64() != 1000 {
+		t.Errorf("second Tx changed with the first: nonce %d, to %s, amount %s", second.Nonce, second.To, second.Amount)
+	}
+	third, err := b.Nonce(2).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Nonce != 1 || third.Nonce != 2 {
+		t.Errorf("nonces %d and %d, want 1 and 2", second.Nonce, third.Nonce)
+	}
+}
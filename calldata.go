@@ -0,0 +1,62 @@
+package zinc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// executeSignature is the function a consuming contract exposes to receive a
+// zkSync transaction: the SerializeTx bytes, the L2 pubKey || signature, the
+// 65-byte Ethereum signature (empty if there is none) and the canonical JSON
+// of the contract arguments.
+const executeSignature = "execute(bytes,bytes,bytes,bytes)"
+
+var executeSelector = keccak256([]byte(executeSignature))[:4]
+
+// ContractCalldata returns the ABI-encoded call of executeSignature for t
+// with args as the contract arguments, usually ContractInput.Arguments. args
+// is encoded as canonical JSON, as in ContractInput.CanonicalJSON.
+func (t *Transaction) ContractCalldata(args interface{}) ([]byte, error) {
+	ser, err := SerializeTx(&t.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHexLen("pubKey", t.Tx.Signature.PubKey, pubKeyLen); err != nil {
+		return nil, err
+	}
+	if err := checkHexLen("signature", t.Tx.Signature.Signature, l2SignatureLen); err != nil {
+		return nil, err
+	}
+	l2Sig, _ := hex.DecodeString(t.Tx.Signature.PubKey + t.Tx.Signature.Signature)
+	var ethSig []byte
+	if t.EthSignature.Signature != "" {
+		if _, _, _, err := ParseEthSignature(t.EthSignature.Signature); err != nil {
+			return nil, err
+		}
+		ethSig, _ = hex.DecodeString(strings.TrimPrefix(t.EthSignature.Signature, "0x"))
+	}
+	argsJSON, err := canonicalJSON(args)
+	if err != nil {
+		return nil, err
+	}
+	return abiEncodeBytesCall(executeSelector, ser, l2Sig, ethSig, argsJSON), nil
+}
+
+// abiEncodeBytesCall ABI-encodes a call whose parameters are all bytes: a
+// head of offsets followed by each value as a length word and its data
+// right-padded to a multiple of 32 bytes.
+func abiEncodeBytesCall(selector []byte, params ...[]byte) []byte {
+	res := append([]byte(nil), selector...)
+	offset := 32 * len(params)
+	var tail []byte
+	for _, p := range params {
+		res = append(res, abiUint(big.NewInt(int64(offset)))...)
+		padded := (len(p) + 31) / 32 * 32
+		tail = append(tail, abiUint(big.NewInt(int64(len(p))))...)
+		tail = append(tail, p...)
+		tail = append(tail, make([]byte, padded-len(p))...)
+		offset += 32 + padded
+	}
+	return append(res, tail...)
+}
@@ -0,0 +1,82 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// word returns n as a hex ABI word.
+func word(n int) string {
+	return fmt.Sprintf("%064x", n)
+}
+
+// zeroPad returns the hex zero bytes that pad n data bytes to a word boundary.
+func zeroPad(n int) string {
+	return strings.Repeat("00", (n+31)/32*32-n)
+}
+
+func TestContractCalldata(t *testing.T) {
+	txn := &Transaction{
+		Tx: *testTransfer(),
+		EthSignature: EthereumSignature{
+			Type:      "EthereumSignature",
+			Signature: "0x" + hex.EncodeToString(cannedEthSignature),
+		},
+	}
+	txn.Tx.Signature = *testL2Signature()
+	got, err := txn.ContractCalldata(map[string]interface{}{"b": 1, "a": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ser := "050000000736615cf349d7f6344891b1e7ca7c72883f5dc049123456781234567812345678123456781234567800036fc23ac0082eeb0000000c000000000000000000000000ffffffff"
+	l2Sig := strings.Repeat("ab", 32) + strings.Repeat("cd", 64)
+	ethSig := hex.EncodeToString(cannedEthSignature)
+	args := hex.EncodeToString([]byte(`{"a":"x","b":1}`))
+	want := concatHex(t,
+		// keccak256("execute(bytes,bytes,bytes,bytes)")[:4], then the offsets.
+		"75db4183",
+		word(0x80), word(0x100), word(0x180), word(0x200),
+		word(74), ser, zeroPad(74),
+		word(96), l2Sig, zeroPad(96),
+		word(65), ethSig, zeroPad(65),
+		word(15), args, zeroPad(15),
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %x\nwant %x", got, want)
+	}
+}
+
+func TestContractCalldataNoEthSignature(t *testing.T) {
+	txn := &Transaction{Tx: *testForcedExit()}
+	txn.Tx.Signature = *testL2Signature()
+	got, err := txn.ContractCalldata(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The Ethereum signature is empty bytes: a zero length and no data.
+	ethSigAt := 4 + 4*32 + 32 + 64 + 32 + 96
+	if offset := got[4+2*32 : 4+3*32]; !bytes.Equal(offset, concatHex(t, word(ethSigAt-4))) {
+		t.Errorf("Ethereum signature offset %x, want %d", offset, ethSigAt-4)
+	}
+	if length := got[ethSigAt : ethSigAt+32]; !bytes.Equal(length, make([]byte, 32)) {
+		t.Errorf("Ethereum signature length %x, want 0", length)
+	}
+}
+
+func TestContractCalldataInvalid(t *testing.T) {
+	unsigned := &Transaction{Tx: *testTransfer()}
+	if _, err := unsigned.ContractCalldata(nil); err == nil {
+		t.Error("transaction without an L2 signature encoded")
+	}
+	badEthSig := &Transaction{
+		Tx:           *testTransfer(),
+		EthSignature: EthereumSignature{Signature: "0x1234"},
+	}
+	badEthSig.Tx.Signature = *testL2Signature()
+	if _, err := badEthSig.ContractCalldata(nil); err == nil {
+		t.Error("2-byte Ethereum signature encoded")
+	}
+}
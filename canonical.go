@@ -0,0 +1,36 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// CanonicalJSON encodes the contract input with every object's keys sorted
+// and no insignificant whitespace, so equal inputs always yield equal bytes.
+// Numbers are emitted exactly as json.Marshal writes them and HTML characters
+// are not escaped.
+func (c *ContractInput) CanonicalJSON() ([]byte, error) {
+	return canonicalJSON(c)
+}
+
+func canonicalJSON(x interface{}) ([]byte, error) {
+	raw, err := json.Marshal(x)
+	if err != nil {
+		return nil, err
+	}
+	// Struct fields marshal in declaration order; decoding into generic maps
+	// and encoding again yields sorted keys at every level.
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
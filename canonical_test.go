@@ -0,0 +1,54 @@
+package zinc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	input := &ContractInput{
+		Arguments: map[string]interface{}{"z": 1, "a": []interface{}{map[string]interface{}{"y": "<b>", "x": true}}},
+		Transaction: Transaction{
+			Tx: Tx{Type: TxTypeForcedExit, Fee: NewBigInt(nil)},
+		},
+	}
+	got, err := input.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys are sorted at every level, including struct fields, and HTML
+	// characters are left as they are.
+	want := `{"arguments":{"a":[{"x":true,"y":"<b>"}],"z":1},"transaction":{"ethereumSignature":{"signature":"","type":""},` +
+		`"tx":{"accountId":0,"amount":"0","fee":"0","from":"","nonce":0,"signature":{"pubKey":"","signature":""},` +
+		`"to":"","token":0,"type":"ForcedExit","validFrom":0,"validUntil":0}}}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	// Decoding and encoding again gives the same bytes.
+	var parsed ContractInput
+	if err := json.Unmarshal(got, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	again, err := parsed.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(got) {
+		t.Errorf("not stable:\n%s\n%s", got, again)
+	}
+}
+
+func TestCanonicalJSONLargeNumbers(t *testing.T) {
+	// Beyond float64 precision: a float round trip would print
+	// 12345678901234567000 and 1e+30.
+	args := json.RawMessage(`{"big":12345678901234567891,"huge":1000000000000000000000000000001,"frac":0.1000000000000000055511}`)
+	got, err := canonicalJSON(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"big":12345678901234567891,"frac":0.1000000000000000055511,"huge":1000000000000000000000000000001}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package zinc
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// WithChecksumValidation rejects addresses whose mixed-case spelling does not
+// match their EIP-55 checksum. All-lowercase and all-uppercase addresses carry
+// no checksum and are accepted as before.
+func WithChecksumValidation() SerializeOption {
+	return func(c *serializeConfig) {
+		c.checksum = true
+	}
+}
+
+func (c serializeConfig) appendAddress(dst []byte, address string) ([]byte, error) {
+	prefixless, err := removeAddressPrefix(address, c.prefixes)
+	if err != nil {
+		return nil, err
+	}
+	dst, err = appendHexAddress(dst, prefixless)
+	if err != nil {
+		return nil, err
+	}
+	if c.checksum {
+		if err := validateAddressChecksum(prefixless); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// validateAddressChecksum checks the case of each letter of a 40-digit hex
+// address against the Keccak-256 hash of its lowercase form: a letter must be
+// uppercase exactly when the matching hash nibble is 8 or more.
+func validateAddressChecksum(hexAddress string) error {
+	lower := strings.ToLower(hexAddress)
+	if hexAddress == lower || hexAddress == strings.ToUpper(hexAddress) {
+		return nil
+	}
+	want := eip55(lower)
+	if hexAddress != want {
+		return fmt.Errorf("Address checksum mismatch: got 0x%s, want 0x%s", hexAddress, want)
+	}
+	return nil
+}
+
+// eip55 returns the checksummed spelling of a lowercase hex address.
+func eip55(lower string) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := h.Sum(nil)
+	res := []byte(lower)
+	for i, c := range res {
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && c <= 'f' && nibble&0xf >= 8 {
+			res[i] = c - 'a' + 'A'
+		}
+	}
+	return string(res)
+}
@@ -0,0 +1,55 @@
+package zinc
+
+import (
+	"strings"
+	"testing"
+)
+
+// eip55Vectors are the mixed-case examples of the EIP-55 specification.
+var eip55Vectors = []string{
+	"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"fB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"dbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"D1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestEIP55(t *testing.T) {
+	for _, want := range eip55Vectors {
+		if got := eip55(strings.ToLower(want)); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestWithChecksumValidation(t *testing.T) {
+	serialize := func(to string) error {
+		tx := testTransfer()
+		tx.To = to
+		_, err := SerializeTx(tx, WithChecksumValidation())
+		return err
+	}
+	for _, v := range eip55Vectors {
+		if err := serialize("0x" + v); err != nil {
+			t.Errorf("checksummed 0x%s: %v", v, err)
+		}
+		// All-lowercase and all-uppercase carry no checksum.
+		if err := serialize("0x" + strings.ToLower(v)); err != nil {
+			t.Errorf("lowercase 0x%s: %v", strings.ToLower(v), err)
+		}
+		if err := serialize("0x" + strings.ToUpper(v)); err != nil {
+			t.Errorf("uppercase 0x%s: %v", strings.ToUpper(v), err)
+		}
+	}
+
+	// Swapping the case of one letter breaks the checksum.
+	mangled := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"
+	if err := serialize(mangled); err == nil {
+		t.Errorf("%s accepted", mangled)
+	}
+	// Without the option the case is not checked.
+	tx := testTransfer()
+	tx.To = mangled
+	if _, err := SerializeTx(tx); err != nil {
+		t.Errorf("%s without WithChecksumValidation: %v", mangled, err)
+	}
+}
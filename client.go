@@ -0,0 +1,305 @@
+package zinc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Client talks to a zkSync JSON-RPC endpoint.
+type Client struct {
+	Endpoint string
+	// HTTPClient sends the requests. Set it with WithHTTPClient to add
+	// timeouts, middleware or a fake transport in tests.
+	HTTPClient *http.Client
+
+	lastId     uint64
+	nonceRetry *nonceRetry
+}
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient makes the Client send its requests with httpClient instead
+// of the shared pooling client.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// nonceRetry holds what SubmitTx needs to re-sign a transaction after a nonce
+// mismatch.
+type nonceRetry struct {
+	account  *Account
+	resolver TokenResolver
+}
+
+// WithAutoNonceRetry makes SubmitTx handle a nonce mismatch by fetching the
+// committed nonce of the sender, re-signing the transaction with account and
+// submitting it once more. account must hold the keys that signed the
+// transactions; resolver supplies the token symbol and decimals to re-sign
+// the Ethereum message of a Transfer. Transactions whose Ethereum
+// authorization cannot be rebuilt, such as a ChangePubKey with EthAuthData,
+// are not retried.
+func WithAutoNonceRetry(account *Account, resolver TokenResolver) ClientOption {
+	return func(c *Client) {
+		c.nonceRetry = &nonceRetry{account: account, resolver: resolver}
+	}
+}
+
+// defaultHTTPClient is shared by all Clients without WithHTTPClient, so they
+// reuse one pool of keep-alive connections. A relayer talks to one or two
+// nodes, so it keeps many more idle connections per host than the standard
+// transport's 2.
+var defaultHTTPClient = &http.Client{
+	Transport: newPoolingTransport(),
+	Timeout:   30 * time.Second,
+}
+
+func newPoolingTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = 100
+	t.IdleConnTimeout = 90 * time.Second
+	return t
+}
+
+// NewClient returns a Client for the JSON-RPC endpoint at endpoint, such as
+// https://api.zksync.io/jsrpc.
+func NewClient(endpoint string, opts ...ClientOption) *Client {
+	c := &Client{
+		Endpoint:   endpoint,
+		HTTPClient: defaultHTTPClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RPCError is an error object returned by the server in a JSON-RPC response.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Id      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Id     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// SubmitTx submits a signed transaction with tx_submit and returns the hash
+// the server assigned to it. ethSig may be nil for transactions that need no
+// Ethereum signature. With WithAutoNonceRetry, a nonce mismatch is retried
+// once with a re-signed copy of tx; tx itself is left unchanged.
+func (c *Client) SubmitTx(ctx context.Context, tx *Tx, ethSig *EthereumSignature) (string, error) {
+	hash, err := c.submitTx(ctx, tx, ethSig)
+	if err == nil || c.nonceRetry == nil || !isNonceMismatch(err) || tx.EthAuthData != nil {
+		return hash, err
+	}
+	retryTx, retrySig, resignErr := c.nonceRetry.resign(ctx, c, tx, ethSig)
+	if resignErr != nil {
+		return "", fmt.Errorf("%w; retry failed: %v", err, resignErr)
+	}
+	return c.submitTx(ctx, retryTx, retrySig)
+}
+
+func (c *Client) submitTx(ctx context.Context, tx *Tx, ethSig *EthereumSignature) (string, error) {
+	var hash string
+	if err := c.call(ctx, "tx_submit", []interface{}{tx, ethSig}, &hash); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// isNonceMismatch reports whether err is the node rejecting a transaction
+// for its nonce.
+func isNonceMismatch(err error) bool {
+	var rpcErr *RPCError
+	return errors.As(err, &rpcErr) && strings.Contains(strings.ToLower(rpcErr.Message), "nonce mismatch")
+}
+
+// resign returns a copy of tx with the committed nonce of its sender, signed
+// again by r.account. The Ethereum signature is redone if there was one.
+func (r *nonceRetry) resign(ctx context.Context, c *Client, tx *Tx, ethSig *EthereumSignature) (*Tx, *EthereumSignature, error) {
+	state, err := c.AccountInfo(ctx, r.account.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+	retry := *tx
+	retry.Nonce = state.Committed.Nonce
+	var sig *Signature
+	if retry.Type == TxTypeTransfer {
+		sig, err = r.account.SignTransfer(&retry)
+	} else {
+		sig, err = SignTx(&retry, r.account.PrivateKey)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	retry.AttachSignature(sig)
+	if ethSig == nil {
+		return &retry, nil, nil
+	}
+	msg, err := TransferEthMessage(&retry, r.resolver)
+	if err != nil {
+		return nil, nil, err
+	}
+	ethSig, err = SignEthMessage([]byte(msg), r.account.EthSigner)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &retry, ethSig, nil
+}
+
+// NewSubmitRequest returns the HTTP request SubmitTx would send for tx,
+// without sending it, for callers that route requests through their own
+// middleware. ethSig is the optional Ethereum signature; at most one may be
+// given. The response is a JSON-RPC response whose result is the tx hash.
+func (c *Client) NewSubmitRequest(ctx context.Context, tx *Tx, ethSig ...*EthereumSignature) (*http.Request, error) {
+	var sig *EthereumSignature
+	switch len(ethSig) {
+	case 0:
+	case 1:
+		sig = ethSig[0]
+	default:
+		return nil, fmt.Errorf("Expected at most one Ethereum signature, got %d", len(ethSig))
+	}
+	return c.newHTTPRequest(ctx, c.newRequest("tx_submit", []interface{}{tx, sig}))
+}
+
+func (c *Client) newRequest(method string, params []interface{}) rpcRequest {
+	return rpcRequest{
+		JSONRPC: "2.0",
+		Id:      atomic.AddUint64(&c.lastId, 1),
+		Method:  method,
+		Params:  params,
+	}
+}
+
+// dryRunOptions is the trailing tx_submit parameter understood by nodes
+// that support dry runs.
+type dryRunOptions struct {
+	DryRun bool `json:"dryRun"`
+}
+
+// DryRunTx asks the node to validate tx with tx_submit in dry-run mode,
+// without executing or storing it. Validation failures come back as
+// *RPCError. Nodes without dry-run support may ignore the flag and submit tx
+// for real, so only use this against nodes known to support it.
+func (c *Client) DryRunTx(ctx context.Context, tx *Tx) error {
+	var hash string
+	return c.call(ctx, "tx_submit", []interface{}{tx, nil, dryRunOptions{DryRun: true}}, &hash)
+}
+
+// call performs a JSON-RPC call and decodes its result into result. An error
+// object in the response is returned as *RPCError.
+func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	var rpcRes rpcResponse
+	status, err := c.post(ctx, method, c.newRequest(method, params), &rpcRes)
+	if err != nil {
+		return err
+	}
+	if rpcRes.Error != nil {
+		return rpcRes.Error
+	}
+	if err := checkStatus(method, status); err != nil {
+		return err
+	}
+	return json.Unmarshal(rpcRes.Result, result)
+}
+
+// callBatch sends reqs as one JSON-RPC batch and returns the responses in the
+// order of reqs. Errors of individual calls are left in the responses.
+func (c *Client) callBatch(ctx context.Context, reqs []rpcRequest) ([]rpcResponse, error) {
+	var rpcRes []rpcResponse
+	status, err := c.post(ctx, "batch", reqs, &rpcRes)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus("batch", status); err != nil {
+		return nil, err
+	}
+	byId := make(map[uint64]rpcResponse, len(rpcRes))
+	for _, r := range rpcRes {
+		byId[r.Id] = r
+	}
+	res := make([]rpcResponse, len(reqs))
+	for i, req := range reqs {
+		r, ok := byId[req.Id]
+		if !ok {
+			return nil, fmt.Errorf("%s: no response to request %d in batch", req.Method, req.Id)
+		}
+		res[i] = r
+	}
+	return res, nil
+}
+
+// post sends body to the endpoint, decodes the JSON reply into res and
+// returns the HTTP status code. name labels errors.
+func (c *Client) post(ctx context.Context, name string, body interface{}, res interface{}) (int, error) {
+	req, err := c.newHTTPRequest(ctx, body)
+	if err != nil {
+		return 0, err
+	}
+	httpRes, err := c.httpClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer httpRes.Body.Close()
+	if err := json.NewDecoder(httpRes.Body).Decode(res); err != nil {
+		if err := checkStatus(name, httpRes.StatusCode); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%s: invalid JSON-RPC response: %w", name, err)
+	}
+	return httpRes.StatusCode, nil
+}
+
+// newHTTPRequest returns the POST of body as JSON to the endpoint.
+func (c *Client) newHTTPRequest(ctx context.Context, body interface{}) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func checkStatus(name string, status int) error {
+	if status != http.StatusOK {
+		return fmt.Errorf("%s: unexpected HTTP status %d %s", name, status, http.StatusText(status))
+	}
+	return nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return defaultHTTPClient
+	}
+	return c.HTTPClient
+}
@@ -0,0 +1,375 @@
+package zinc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRPCRequest is a JSON-RPC request as the mock node sees it, with the
+// params left raw for the test to decode.
+type testRPCRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	Id      uint64            `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type testRPCResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Id      uint64      `json:"id"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *RPCError   `json:"error,omitempty"`
+}
+
+// newMockNode returns a Client for a node that answers each single JSON-RPC
+// request with handle.
+func newMockNode(t *testing.T, handle func(req testRPCRequest) (interface{}, *RPCError)) *Client {
+	t.Helper()
+	return newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("HTTP method %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type %q, want application/json", ct)
+		}
+		var req testRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.JSONRPC != "2.0" {
+			t.Errorf("jsonrpc %q, want 2.0", req.JSONRPC)
+		}
+		result, rpcErr := handle(req)
+		json.NewEncoder(w).Encode(testRPCResponse{JSONRPC: "2.0", Id: req.Id, Result: result, Error: rpcErr})
+	})
+}
+
+// newMockServer returns a Client for a node served by handler.
+func newMockServer(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL)
+}
+
+func TestSubmitTx(t *testing.T) {
+	tx := testTransfer()
+	ethSig := &EthereumSignature{Type: "EthereumSignature", Signature: "0x1234"}
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if req.Method != "tx_submit" {
+			t.Errorf("method %q, want tx_submit", req.Method)
+		}
+		// Handlers run off the test goroutine, so they report with Errorf.
+		if len(req.Params) != 2 {
+			t.Errorf("%d params, want 2", len(req.Params))
+			return nil, &RPCError{Code: -32602, Message: "Invalid params"}
+		}
+		var gotTx Tx
+		if err := json.Unmarshal(req.Params[0], &gotTx); err != nil {
+			t.Errorf("decoding tx: %v", err)
+		}
+		assertSameTx(t, &gotTx, tx)
+		var gotSig EthereumSignature
+		if err := json.Unmarshal(req.Params[1], &gotSig); err != nil {
+			t.Errorf("decoding eth signature: %v", err)
+		}
+		if gotSig != *ethSig {
+			t.Errorf("eth signature %+v, want %+v", gotSig, *ethSig)
+		}
+		return "sync-tx:abcd", nil
+	})
+	hash, err := client.SubmitTx(context.Background(), tx, ethSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "sync-tx:abcd" {
+		t.Errorf("hash %q, want sync-tx:abcd", hash)
+	}
+}
+
+func TestSubmitTxNoEthSignature(t *testing.T) {
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if len(req.Params) != 2 || string(req.Params[1]) != "null" {
+			t.Errorf("params %s, want the tx and null", req.Params)
+		}
+		return "sync-tx:abcd", nil
+	})
+	if _, err := client.SubmitTx(context.Background(), testTransfer(), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSubmitTxRPCError(t *testing.T) {
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		return nil, &RPCError{Code: 101, Message: "Nonce mismatch"}
+	})
+	_, err := client.SubmitTx(context.Background(), testTransfer(), nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("got %v, want an *RPCError", err)
+	}
+	if rpcErr.Code != 101 || rpcErr.Message != "Nonce mismatch" {
+		t.Errorf("got code %d, message %q, want 101, Nonce mismatch", rpcErr.Code, rpcErr.Message)
+	}
+}
+
+func TestSubmitTxHTTPStatus(t *testing.T) {
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	})
+	if _, err := client.SubmitTx(context.Background(), testTransfer(), nil); err == nil {
+		t.Fatal("503 response accepted")
+	}
+}
+
+func TestSubmitTxContextCanceled(t *testing.T) {
+	// The node never answers; the handler is released once the test is done.
+	release := make(chan struct{})
+	defer close(release)
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.SubmitTx(ctx, testTransfer(), nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestDryRunTx(t *testing.T) {
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if req.Method != "tx_submit" {
+			t.Errorf("method %q, want tx_submit", req.Method)
+		}
+		if len(req.Params) != 3 {
+			t.Errorf("%d params, want the tx, null and the dry-run flag", len(req.Params))
+			return nil, &RPCError{Code: -32602, Message: "Invalid params"}
+		}
+		if got := string(req.Params[2]); got != `{"dryRun":true}` {
+			t.Errorf("options %s, want {\"dryRun\":true}", got)
+		}
+		var tx Tx
+		if err := json.Unmarshal(req.Params[0], &tx); err != nil {
+			t.Errorf("decoding tx: %v", err)
+		}
+		if tx.Nonce != 12 {
+			return nil, &RPCError{Code: 101, Message: "Nonce mismatch"}
+		}
+		return "sync-tx:abcd", nil
+	})
+	if err := client.DryRunTx(context.Background(), testTransfer()); err != nil {
+		t.Errorf("valid tx: %v", err)
+	}
+
+	bad := testTransfer()
+	bad.Nonce = 3
+	err := client.DryRunTx(context.Background(), bad)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != 101 {
+		t.Errorf("bad nonce: got %v, want *RPCError code 101", err)
+	}
+}
+
+// countingTransport counts the requests it passes on to base.
+type countingTransport struct {
+	base     http.RoundTripper
+	requests int32
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	return t.base.RoundTrip(r)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(testRPCResponse{JSONRPC: "2.0", Id: 1, Result: "sync-tx:abcd"})
+	}))
+	defer srv.Close()
+	transport := &countingTransport{base: http.DefaultTransport}
+	client := NewClient(srv.URL, WithHTTPClient(&http.Client{Transport: transport}))
+	if _, err := client.SubmitTx(context.Background(), testTransfer(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&transport.requests); n != 1 {
+		t.Errorf("injected client sent %d requests, want 1", n)
+	}
+}
+
+func TestNewClientPoolingDefaults(t *testing.T) {
+	a, b := NewClient("http://a"), NewClient("http://b")
+	if a.HTTPClient != b.HTTPClient {
+		t.Error("Clients without WithHTTPClient do not share a connection pool")
+	}
+	transport, ok := a.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("default transport is %T, want *http.Transport", a.HTTPClient.Transport)
+	}
+	if transport.MaxIdleConnsPerHost < 100 || transport.DisableKeepAlives {
+		t.Errorf("MaxIdleConnsPerHost %d, keep-alives disabled %v", transport.MaxIdleConnsPerHost, transport.DisableKeepAlives)
+	}
+}
+
+func TestSubmitTxAutoNonceRetry(t *testing.T) {
+	account := testAccount(t)
+	tx := testTransfer()
+	tx.From = hardhatAddress
+	tx.Token = 0
+	txn, err := account.SignTransferFull(tx, nil, "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The node has moved on to nonce 15 since tx was signed with 12.
+	var submits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req testRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			return
+		}
+		res := testRPCResponse{JSONRPC: "2.0", Id: req.Id}
+		switch req.Method {
+		case "account_info":
+			res.Result = json.RawMessage(`{"address": "` + hardhatAddress + `", "id": 7, "committed": {"nonce": 15}, "verified": {"nonce": 15}}`)
+		case "tx_submit":
+			atomic.AddInt32(&submits, 1)
+			var got Transaction
+			if len(req.Params) != 2 || json.Unmarshal(req.Params[0], &got.Tx) != nil || json.Unmarshal(req.Params[1], &got.EthSignature) != nil {
+				t.Errorf("params %s, want a tx and its eth signature", req.Params)
+				return
+			}
+			if got.Tx.Nonce != 15 {
+				res.Error = &RPCError{Code: 101, Message: "Nonce mismatch"}
+				break
+			}
+			if ok, err := VerifyEthSignature(&got, "ETH", 18); err != nil || !ok {
+				t.Errorf("retried tx has no valid eth signature: %v, %v", ok, err)
+			}
+			res.Result = "sync-tx:abcd"
+		default:
+			t.Errorf("unexpected method %q", req.Method)
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithAutoNonceRetry(account, TokenList{ETH}))
+	hash, err := client.SubmitTx(context.Background(), &txn.Tx, &txn.EthSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "sync-tx:abcd" {
+		t.Errorf("hash %q, want sync-tx:abcd", hash)
+	}
+	if n := atomic.LoadInt32(&submits); n != 2 {
+		t.Errorf("%d submits, want 2", n)
+	}
+	if txn.Tx.Nonce != 12 {
+		t.Errorf("caller's tx changed to nonce %d", txn.Tx.Nonce)
+	}
+
+	// Without the option the mismatch is returned as is.
+	atomic.StoreInt32(&submits, 0)
+	plain := NewClient(srv.URL)
+	if _, err := plain.SubmitTx(context.Background(), &txn.Tx, &txn.EthSignature); !isNonceMismatch(err) {
+		t.Errorf("got %v, want the nonce mismatch", err)
+	}
+	if n := atomic.LoadInt32(&submits); n != 1 {
+		t.Errorf("%d submits without retry, want 1", n)
+	}
+}
+
+func TestSubmitTxAutoNonceRetryOnce(t *testing.T) {
+	var submits int32
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		if req.Method == "account_info" {
+			return json.RawMessage(`{"address": "` + hardhatAddress + `", "id": 7, "committed": {"nonce": 15}, "verified": {"nonce": 15}}`), nil
+		}
+		atomic.AddInt32(&submits, 1)
+		return nil, &RPCError{Code: 101, Message: "Nonce mismatch"}
+	})
+	WithAutoNonceRetry(testAccount(t), TokenList{ETH})(client)
+	tx := testTransfer()
+	tx.From = hardhatAddress
+	if _, err := client.SubmitTx(context.Background(), tx, nil); !isNonceMismatch(err) {
+		t.Errorf("got %v, want the nonce mismatch", err)
+	}
+	if n := atomic.LoadInt32(&submits); n != 2 {
+		t.Errorf("%d submits, want the first and one retry", n)
+	}
+}
+
+func TestNewSubmitRequest(t *testing.T) {
+	client := NewClient("https://node.example/jsrpc")
+	tx := testTransfer()
+	ethSig := &EthereumSignature{Type: "EthereumSignature", Signature: "0x1234"}
+	req, err := client.NewSubmitRequest(context.Background(), tx, ethSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != http.MethodPost || req.URL.String() != "https://node.example/jsrpc" {
+		t.Errorf("%s %s, want POST to the endpoint", req.Method, req.URL)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type %q, want application/json", ct)
+	}
+	var body testRPCRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.JSONRPC != "2.0" || body.Method != "tx_submit" || len(body.Params) != 2 {
+		t.Fatalf("body %+v, want a tx_submit with 2 params", body)
+	}
+	wantTx, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body.Params[0]) != string(wantTx) {
+		t.Errorf("tx param %s, want %s", body.Params[0], wantTx)
+	}
+	if got := string(body.Params[1]); got != `{"type":"EthereumSignature","signature":"0x1234"}` {
+		t.Errorf("eth signature param %s", got)
+	}
+
+	// Without a signature the second param is null, as with SubmitTx.
+	req, err = client.NewSubmitRequest(context.Background(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.NewDecoder(req.Body).Decode(&body)
+	if len(body.Params) != 2 || string(body.Params[1]) != "null" {
+		t.Errorf("params %s, want the tx and null", body.Params)
+	}
+}
+
+func TestNewSubmitRequestSends(t *testing.T) {
+	// The request works as is against a node.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req testRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(testRPCResponse{JSONRPC: "2.0", Id: req.Id, Result: "sync-tx:abcd"})
+	}))
+	defer srv.Close()
+	req, err := NewClient(srv.URL).NewSubmitRequest(context.Background(), testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var got testRPCResponse
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil || got.Result != "sync-tx:abcd" {
+		t.Errorf("response %+v, %v", got, err)
+	}
+}
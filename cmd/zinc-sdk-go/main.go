@@ -0,0 +1,57 @@
+// Command zinc-sdk-go serializes the transaction in data/input.json and logs
+// the bytes. Build it from the repository root so the zksync-crypto library
+// in libs is found:
+//
+//	CGO_LDFLAGS="-L$PWD/libs" go build ./cmd/zinc-sdk-go
+//
+// The input is a contract input such as:
+//
+//	"transaction": {
+//	  "tx": {
+//	    "type": "Transfer",
+//	    "accountId": 1,
+//	    "from": "0x36615cf349d7f6344891b1e7ca7c72883f5dc049",
+//	    "to": "0x1234567812345678123456781234567812345678",
+//	    "token": 0,
+//	    "amount": "0",
+//	    "fee": "37500000000000",
+//	    "nonce": 2,
+//	    "signature": {
+//	      "pubKey": "07f86efb9bf58d5ebf23042406cb43e9363879ff79223be05b7feac1dbc58c86",
+//	      "signature": "042c7356c3970c5ab620e1eaf0a9e39563edc9383072ac33a29398f11678b2a3acdc40ff05acd225b6a71962cfabfa6012fae8492106987bcd48135fefa09c02"
+//	    }
+//	  },
+//	  "ethereumSignature": {
+//	    "type": "EthereumSignature",
+//	    "signature": "0xbe7a011c0b03a2ab8eceb3f51ec3055e5998b025e3e41a320f6b00532a4c49604608fe7b9c36d837c36817bbaf5570197484281dd45d83f2d9ef867b7454b91e1b"
+//	  }
+//	}
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	zinc "github.com/motxx/zinc-sdk-go"
+)
+
+func main() {
+	bytes, err := os.ReadFile("data/input.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var input zinc.ContractInput
+	if err = json.Unmarshal(bytes, &input); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("input: %v\n", input)
+
+	ser, err := zinc.SerializeTx(&input.Transaction.Tx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%v\n", ser)
+}
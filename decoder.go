@@ -0,0 +1,116 @@
+package zinc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// maxFrameSize bounds the length ReadFramed accepts, so a corrupt prefix
+// cannot make it allocate gigabytes. Serialized transactions are far smaller.
+const maxFrameSize = 1 << 16
+
+// Decoder reads serialized transactions back to back from a stream, such as a
+// log written with SerializeTxTo. Only the ProtocolV1 layout without
+// extensions is supported, since its leading type byte fixes the length.
+type Decoder struct {
+	r io.Reader
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Next reads and decodes the next transaction. It returns io.EOF when the
+// stream ends cleanly between transactions and io.ErrUnexpectedEOF when it
+// ends inside one.
+func (d *Decoder) Next() (*Tx, error) {
+	var typeByte [1]byte
+	if _, err := io.ReadFull(d.r, typeByte[:]); err != nil {
+		return nil, err
+	}
+	txType, ok := txTypeOf(typeByte[:])
+	if !ok {
+		return nil, fmt.Errorf("Unknown transaction type byte: 0x%02x", typeByte[0])
+	}
+	n, err := txLength(txType)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, n)
+	data[0] = typeByte[0]
+	if _, err := io.ReadFull(d.r, data[1:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return DeserializeTx(data)
+}
+
+// WriteFramed writes the serialization of tx behind a 4-byte big-endian
+// length prefix. Unlike the bare stream read by Decoder, framed streams can
+// carry any layout SerializeTx produces.
+func WriteFramed(w io.Writer, tx *Tx) error {
+	data, err := SerializeTx(tx)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	_, err = w.Write(append(frame, data...))
+	return err
+}
+
+// ReadFramed reads and decodes one transaction written by WriteFramed. It
+// returns io.EOF when r ends cleanly between frames and io.ErrUnexpectedEOF
+// when it ends inside one.
+func ReadFramed(r io.Reader) (*Tx, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(prefix[:])
+	if n > maxFrameSize {
+		return nil, fmt.Errorf("Frame of %d bytes exceeds the maximum of %d", n, maxFrameSize)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return DeserializeTx(data)
+}
+
+// crcSize is the length of the CRC-32 SerializeWithCRC appends.
+const crcSize = 4
+
+// SerializeWithCRC returns the SerializeTx bytes of tx followed by their
+// IEEE CRC-32, big-endian, to detect corruption on lossy transports.
+func SerializeWithCRC(tx *Tx) ([]byte, error) {
+	data, err := SerializeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	var sum [crcSize]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(data))
+	return append(data, sum[:]...), nil
+}
+
+// DeserializeWithCRC checks and strips the CRC-32 appended by
+// SerializeWithCRC and decodes the transaction.
+func DeserializeWithCRC(data []byte) (*Tx, error) {
+	if len(data) < crcSize {
+		return nil, fmt.Errorf("Data too short for a CRC: %d bytes", len(data))
+	}
+	body := data[:len(data)-crcSize]
+	want := binary.BigEndian.Uint32(data[len(body):])
+	if got := crc32.ChecksumIEEE(body); got != want {
+		return nil, fmt.Errorf("CRC mismatch: data has %08x, computed %08x", want, got)
+	}
+	return DeserializeTx(body)
+}
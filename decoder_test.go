@@ -0,0 +1,176 @@
+package zinc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testStream returns one transaction of each type and their serializations
+// written back to back.
+func testStream(t *testing.T) ([]*Tx, []byte) {
+	t.Helper()
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	txs := []*Tx{testTransfer(), testChangePubKey(), withdraw, testForcedExit()}
+	var buf bytes.Buffer
+	for _, tx := range txs {
+		if _, err := SerializeTxTo(&buf, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return txs, buf.Bytes()
+}
+
+func TestDecoder(t *testing.T) {
+	txs, stream := testStream(t)
+	// A reader returning one byte at a time checks that Next does not rely
+	// on whole transactions arriving in one Read.
+	dec := NewDecoder(&oneByteReader{bytes.NewReader(stream)})
+	for i, want := range txs {
+		got, err := dec.Next()
+		if err != nil {
+			t.Fatalf("transaction %d: %v", i, err)
+		}
+		assertSameTx(t, got, want)
+	}
+	if _, err := dec.Next(); err != io.EOF {
+		t.Errorf("after the last transaction got %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderEmpty(t *testing.T) {
+	if _, err := NewDecoder(bytes.NewReader(nil)).Next(); err != io.EOF {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderTruncated(t *testing.T) {
+	_, stream := testStream(t)
+	dec := NewDecoder(bytes.NewReader(stream[:len(stream)-1]))
+	var err error
+	for i := 0; err == nil; i++ {
+		if i > 4 {
+			t.Fatal("no error from a truncated stream")
+		}
+		_, err = dec.Next()
+	}
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	if _, err := NewDecoder(bytes.NewReader([]byte{0x42})).Next(); err == nil {
+		t.Error("unknown type byte decoded")
+	}
+	errRead := errors.New("connection reset")
+	dec := NewDecoder(io.MultiReader(bytes.NewReader([]byte{0x05, 0x00}), &errReader{errRead}))
+	if _, err := dec.Next(); !errors.Is(err, errRead) {
+		t.Errorf("got %v, want the reader's error", err)
+	}
+}
+
+func TestFramedRoundTrip(t *testing.T) {
+	txs, _ := testStream(t)
+	pr, pw := io.Pipe()
+	go func() {
+		for _, tx := range txs {
+			if err := WriteFramed(pw, tx); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.Close()
+	}()
+	for i, want := range txs {
+		got, err := ReadFramed(pr)
+		if err != nil {
+			t.Fatalf("transaction %d: %v", i, err)
+		}
+		assertSameTx(t, got, want)
+	}
+	if _, err := ReadFramed(pr); err != io.EOF {
+		t.Errorf("after the last frame got %v, want io.EOF", err)
+	}
+}
+
+func TestReadFramedTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFramed(&buf, testTransfer()); err != nil {
+		t.Fatal(err)
+	}
+	frame := buf.Bytes()
+	// Cut inside the length prefix and inside the body.
+	for _, n := range []int{2, len(frame) - 1} {
+		if _, err := ReadFramed(bytes.NewReader(frame[:n])); err != io.ErrUnexpectedEOF {
+			t.Errorf("%d of %d bytes: got %v, want io.ErrUnexpectedEOF", n, len(frame), err)
+		}
+	}
+	if _, err := ReadFramed(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff})); err == nil {
+		t.Error("4 GiB frame length accepted")
+	}
+}
+
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return r.r.Read(p[:1])
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestCRCRoundTrip(t *testing.T) {
+	txs, _ := testStream(t)
+	for _, tx := range txs {
+		data, err := SerializeWithCRC(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain, err := SerializeTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data[:len(plain)], plain) || len(data) != len(plain)+4 {
+			t.Errorf("%s: %x is not the serialization plus 4 bytes", tx.Type, data)
+		}
+		got, err := DeserializeWithCRC(data)
+		if err != nil {
+			t.Fatalf("%s: %v", tx.Type, err)
+		}
+		assertSameTx(t, got, tx)
+	}
+}
+
+func TestCRCCorruption(t *testing.T) {
+	data, err := SerializeWithCRC(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Flip one bit in every position, body and CRC alike.
+	for i := range data {
+		corrupt := append([]byte(nil), data...)
+		corrupt[i] ^= 0x10
+		if _, err := DeserializeWithCRC(corrupt); err == nil {
+			t.Errorf("bit flip at byte %d not detected", i)
+		}
+	}
+	if _, err := DeserializeWithCRC(data[:len(data)-1]); err == nil {
+		t.Error("truncated data accepted")
+	}
+	if _, err := DeserializeWithCRC(data[:3]); err == nil {
+		t.Error("3 bytes accepted")
+	}
+}
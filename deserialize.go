@@ -0,0 +1,166 @@
+package zinc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// fieldReader reads consecutive fixed-size fields from a serialization.
+// Callers check the total length up front, so next never runs short.
+type fieldReader struct {
+	data []byte
+}
+
+func (r *fieldReader) next(n int) []byte {
+	field := r.data[:n]
+	r.data = r.data[n:]
+	return field
+}
+
+func (r *fieldReader) uint(n int) uint64 {
+	var buf [8]byte
+	copy(buf[8-n:], r.next(n))
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+func (r *fieldReader) address() string {
+	return "0x" + hex.EncodeToString(r.next(20))
+}
+
+func (r *fieldReader) pubKeyHash() string {
+	return "sync:" + hex.EncodeToString(r.next(20))
+}
+
+// checkSerialized reports an error unless data has the length and type byte
+// of a txType serialized in the ProtocolV1 layout.
+func checkSerialized(data []byte, txType TxType) error {
+	n, err := txLength(txType)
+	if err != nil {
+		return err
+	}
+	if len(data) != n {
+		return fmt.Errorf("%s must be %d bytes long. len: %d", txType, n, len(data))
+	}
+	b, err := TypeByte(txType)
+	if err != nil {
+		return err
+	}
+	if data[0] != b {
+		return fmt.Errorf("Not a %s: type byte 0x%02x, want 0x%02x", txType, data[0], b)
+	}
+	return nil
+}
+
+// DeserializeTx is the inverse of SerializeTx for the default ProtocolV1
+// layout: the type byte picks the transaction type.
+func DeserializeTx(data []byte) (*Tx, error) {
+	txType, ok := txTypeOf(data)
+	if !ok {
+		return nil, fmt.Errorf("Unknown transaction type byte")
+	}
+	switch txType {
+	case TxTypeTransfer:
+		return DeserializeTransfer(data)
+	case TxTypeWithdraw:
+		return deserializeWithdraw(data)
+	case TxTypeChangePubKey:
+		return deserializeChangePubKey(data)
+	case TxTypeForcedExit:
+		return deserializeForcedExit(data)
+	}
+	return nil, fmt.Errorf("Unknown transaction type: %q", txType)
+}
+
+// DeserializeTransfer is the inverse of SerializeTransfer for the default
+// ProtocolV1 layout without extensions. Addresses come back lowercase and the
+// packed amount and fee are expanded to their exact values.
+func DeserializeTransfer(data []byte) (*Tx, error) {
+	if err := checkSerialized(data, TxTypeTransfer); err != nil {
+		return nil, err
+	}
+	r := &fieldReader{data: data[1:]}
+	tx := &Tx{Type: TxTypeTransfer}
+	tx.AccountId = r.uint(4)
+	tx.From = r.address()
+	tx.To = r.address()
+	tx.Token = r.uint(2)
+	amount, err := decodePackedAmount(r.next(packedAmountSize))
+	if err != nil {
+		return nil, err
+	}
+	tx.Amount = NewBigInt(amount)
+	fee, err := decodePackedFee(r.next(packedFeeSize))
+	if err != nil {
+		return nil, err
+	}
+	tx.Fee = NewBigInt(fee)
+	tx.Nonce = r.uint(4)
+	tx.ValidFrom = r.uint(8)
+	tx.ValidUntil = r.uint(8)
+	return tx, nil
+}
+
+func deserializeWithdraw(data []byte) (*Tx, error) {
+	if err := checkSerialized(data, TxTypeWithdraw); err != nil {
+		return nil, err
+	}
+	r := &fieldReader{data: data[1:]}
+	tx := &Tx{Type: TxTypeWithdraw}
+	tx.AccountId = r.uint(4)
+	tx.From = r.address()
+	tx.To = r.address()
+	tx.Token = r.uint(2)
+	tx.Amount = NewBigInt(new(big.Int).SetBytes(r.next(fullAmountSize)))
+	fee, err := decodePackedFee(r.next(packedFeeSize))
+	if err != nil {
+		return nil, err
+	}
+	tx.Fee = NewBigInt(fee)
+	tx.Nonce = r.uint(4)
+	tx.ValidFrom = r.uint(8)
+	tx.ValidUntil = r.uint(8)
+	return tx, nil
+}
+
+func deserializeChangePubKey(data []byte) (*Tx, error) {
+	if err := checkSerialized(data, TxTypeChangePubKey); err != nil {
+		return nil, err
+	}
+	r := &fieldReader{data: data[1:]}
+	tx := &Tx{Type: TxTypeChangePubKey}
+	tx.AccountId = r.uint(4)
+	tx.Account = r.address()
+	tx.NewPkHash = r.pubKeyHash()
+	tx.FeeToken = r.uint(2)
+	fee, err := decodePackedFee(r.next(packedFeeSize))
+	if err != nil {
+		return nil, err
+	}
+	tx.Fee = NewBigInt(fee)
+	tx.Nonce = r.uint(4)
+	tx.ValidFrom = r.uint(8)
+	tx.ValidUntil = r.uint(8)
+	return tx, nil
+}
+
+func deserializeForcedExit(data []byte) (*Tx, error) {
+	if err := checkSerialized(data, TxTypeForcedExit); err != nil {
+		return nil, err
+	}
+	r := &fieldReader{data: data[1:]}
+	tx := &Tx{Type: TxTypeForcedExit}
+	tx.InitiatorAccountId = r.uint(4)
+	tx.Target = r.address()
+	tx.Token = r.uint(2)
+	fee, err := decodePackedFee(r.next(packedFeeSize))
+	if err != nil {
+		return nil, err
+	}
+	tx.Fee = NewBigInt(fee)
+	tx.Nonce = r.uint(4)
+	tx.ValidFrom = r.uint(8)
+	tx.ValidUntil = r.uint(8)
+	return tx, nil
+}
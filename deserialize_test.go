@@ -0,0 +1,78 @@
+package zinc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// assertSameTx compares transactions by their JSON form, which is what the
+// node sees. It does not stop the test, so mock node handlers can use it.
+func assertSameTx(t *testing.T, got, want *Tx) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("got  %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func TestDeserializeTransfer(t *testing.T) {
+	want := testTransfer()
+	data, err := SerializeTransfer(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DeserializeTransfer(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameTx(t, got, want)
+}
+
+func TestDeserializeTx(t *testing.T) {
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	for _, want := range []*Tx{testTransfer(), withdraw, testChangePubKey(), testForcedExit()} {
+		data, err := SerializeTx(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := DeserializeTx(data)
+		if err != nil {
+			t.Errorf("%s: %v", want.Type, err)
+			continue
+		}
+		assertSameTx(t, got, want)
+	}
+}
+
+func TestDeserializeTransferWrongLength(t *testing.T) {
+	data, err := SerializeTransfer(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range [][]byte{data[:len(data)-1], append(data, 0), nil} {
+		if _, err := DeserializeTransfer(bad); err == nil {
+			t.Errorf("%d bytes deserialized without an error", len(bad))
+		}
+	}
+}
+
+func TestDeserializeTransferWrongType(t *testing.T) {
+	data, err := SerializeTransfer(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[0] = 0x03
+	if _, err := DeserializeTransfer(data); err == nil {
+		t.Error("Withdraw type byte deserialized as a Transfer")
+	}
+}
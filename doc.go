@@ -0,0 +1,4 @@
+// Package zinc builds, serializes and signs zkSync transactions and submits
+// them over JSON-RPC. It links the zksync-crypto library through cgo; see
+// cmd/zinc-sdk-go for a minimal program and how to build it.
+package zinc
@@ -0,0 +1,168 @@
+package zinc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// EIP-712 domain of zkSync typed-data signatures. The chain id is part of the
+// domain, so a signature for one network is not valid on another.
+const (
+	eip712DomainName    = "zkSync"
+	eip712DomainVersion = "1"
+)
+
+// TypedDataField is one member of an EIP-712 struct type.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataDomain is the EIP-712 domain.
+type TypedDataDomain struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	ChainId uint64 `json:"chainId"`
+}
+
+// TransferMessage is the EIP-712 message of a Transfer. 64-bit and larger
+// values are written as decimal strings so JavaScript wallets read them
+// exactly.
+type TransferMessage struct {
+	AccountId  uint64 `json:"accountId"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Token      uint64 `json:"token"`
+	Amount     BigInt `json:"amount"`
+	Fee        BigInt `json:"fee"`
+	Nonce      uint64 `json:"nonce"`
+	ValidFrom  uint64 `json:"validFrom,string"`
+	ValidUntil uint64 `json:"validUntil,string"`
+}
+
+// TransferTypedData is the typed data of a Transfer in the JSON shape that
+// eth_signTypedData_v4 takes.
+type TransferTypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      TypedDataDomain             `json:"domain"`
+	Message     TransferMessage             `json:"message"`
+}
+
+var eip712DomainFields = []TypedDataField{
+	{"name", "string"},
+	{"version", "string"},
+	{"chainId", "uint256"},
+}
+
+var transferTypedFields = []TypedDataField{
+	{"accountId", "uint32"},
+	{"from", "address"},
+	{"to", "address"},
+	{"token", "uint32"},
+	{"amount", "uint256"},
+	{"fee", "uint256"},
+	{"nonce", "uint32"},
+	{"validFrom", "uint64"},
+	{"validUntil", "uint64"},
+}
+
+// BuildTransferTypedData returns the EIP-712 typed data of a Transfer on the
+// network with the given chain id.
+func BuildTransferTypedData(tx *Tx, chainId uint64) (*TransferTypedData, error) {
+	if tx.Type != TxTypeTransfer {
+		return nil, fmt.Errorf("Expected a %s, got %q", TxTypeTransfer, tx.Type)
+	}
+	// Reuse the serializer's checks on every field.
+	if _, err := SerializeTransfer(tx); err != nil {
+		return nil, err
+	}
+	return &TransferTypedData{
+		Types: map[string][]TypedDataField{
+			"EIP712Domain": eip712DomainFields,
+			"Transfer":     transferTypedFields,
+		},
+		PrimaryType: "Transfer",
+		Domain: TypedDataDomain{
+			Name:    eip712DomainName,
+			Version: eip712DomainVersion,
+			ChainId: chainId,
+		},
+		Message: TransferMessage{
+			AccountId:  tx.AccountId,
+			From:       strings.ToLower(tx.From),
+			To:         strings.ToLower(tx.To),
+			Token:      tx.Token,
+			Amount:     NewBigInt(new(big.Int).Set(tx.Amount.Value())),
+			Fee:        NewBigInt(new(big.Int).Set(tx.Fee.Value())),
+			Nonce:      tx.Nonce,
+			ValidFrom:  tx.ValidFrom,
+			ValidUntil: tx.ValidUntil,
+		},
+	}, nil
+}
+
+// Hash returns the 32-byte EIP-712 digest a wallet signs for d:
+// keccak256(0x19 0x01 || domainSeparator || hashStruct(message)).
+func (d *TransferTypedData) Hash() ([]byte, error) {
+	from, err := serializeAddress(d.Message.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := serializeAddress(d.Message.To)
+	if err != nil {
+		return nil, err
+	}
+	domain := keccak256(
+		typeHash("EIP712Domain", eip712DomainFields),
+		keccak256([]byte(d.Domain.Name)),
+		keccak256([]byte(d.Domain.Version)),
+		abiUint(new(big.Int).SetUint64(d.Domain.ChainId)),
+	)
+	message := keccak256(
+		typeHash("Transfer", transferTypedFields),
+		abiUint(new(big.Int).SetUint64(d.Message.AccountId)),
+		abiAddress(from),
+		abiAddress(to),
+		abiUint(new(big.Int).SetUint64(d.Message.Token)),
+		abiUint(d.Message.Amount.Value()),
+		abiUint(d.Message.Fee.Value()),
+		abiUint(new(big.Int).SetUint64(d.Message.Nonce)),
+		abiUint(new(big.Int).SetUint64(d.Message.ValidFrom)),
+		abiUint(new(big.Int).SetUint64(d.Message.ValidUntil)),
+	)
+	return keccak256([]byte{0x19, 0x01}, domain, message), nil
+}
+
+// typeHash is keccak256 of the EIP-712 type encoding, e.g.
+// "Transfer(uint32 accountId,address from,...)".
+func typeHash(name string, fields []TypedDataField) []byte {
+	members := make([]string, len(fields))
+	for i, f := range fields {
+		members[i] = f.Type + " " + f.Name
+	}
+	return keccak256([]byte(name + "(" + strings.Join(members, ",") + ")"))
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// abiUint encodes a non-negative integer as a 32-byte ABI word.
+func abiUint(x *big.Int) []byte {
+	return x.FillBytes(make([]byte, 32))
+}
+
+// abiAddress encodes a 20-byte address as a left-padded 32-byte ABI word.
+func abiAddress(address []byte) []byte {
+	word := make([]byte, 32)
+	copy(word[12:], address)
+	return word
+}
@@ -0,0 +1,91 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestEIP712DomainTypeHash(t *testing.T) {
+	// The well-known hash of EIP712Domain(string name,string version,uint256 chainId).
+	const want = "c2f8787176b8ac6bf7215b4adcc1e069bf4ab82d9ab1df05a57a91d425935b6e"
+	if got := hex.EncodeToString(typeHash("EIP712Domain", eip712DomainFields)); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestTransferTypedDataHash(t *testing.T) {
+	// Digests of testTransfer computed independently from the EIP-712
+	// encoding rules.
+	tests := []struct {
+		chainId uint64
+		digest  string
+	}{
+		{1, "edf2477b7216dd73c6a944b73763d52162b3bca19e41e275838345aef77b7a7b"},
+		{5, "ccd70530702316b86a9d25108c34bc68e892af9c73b3fdd838bc48135c7b3981"},
+	}
+	for _, tt := range tests {
+		data, err := BuildTransferTypedData(testTransfer(), tt.chainId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := data.Hash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.digest {
+			t.Errorf("chainId %d: digest %x, want %s", tt.chainId, got, tt.digest)
+		}
+	}
+}
+
+func TestTransferTypedDataChainId(t *testing.T) {
+	mainnet, err := BuildTransferTypedData(testTransfer(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goerli, err := BuildTransferTypedData(testTransfer(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := mainnet.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := goerli.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, b) {
+		t.Error("chainIds 1 and 5 gave the same digest")
+	}
+}
+
+func TestBuildTransferTypedData(t *testing.T) {
+	tx := testTransfer()
+	tx.To = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	data, err := BuildTransferTypedData(tx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(data.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"accountId":7,"from":"` + testFrom + `","to":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8",` +
+		`"token":3,"amount":"1500000000000000000","fee":"37500000000000","nonce":12,` +
+		`"validFrom":"0","validUntil":"4294967295"}`
+	if string(got) != want {
+		t.Errorf("message\n got %s\nwant %s", got, want)
+	}
+	if data.PrimaryType != "Transfer" || data.Domain.Name != "zkSync" || data.Domain.Version != "1" {
+		t.Errorf("primaryType %q, domain %+v", data.PrimaryType, data.Domain)
+	}
+
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	if _, err := BuildTransferTypedData(withdraw, 1); err == nil {
+		t.Error("Withdraw built as Transfer typed data")
+	}
+}
@@ -0,0 +1,31 @@
+package zinc
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// approveSelector is the first 4 bytes of keccak256("approve(address,uint256)").
+var approveSelector = []byte{0x09, 0x5e, 0xa7, 0xb3}
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ApproveCalldata returns the ABI-encoded ERC-20 approve(spender, amount) call
+// to send to token before depositing it. spender is usually the zkSync
+// contract address.
+func ApproveCalldata(token Token, spender []byte, amount *big.Int) ([]byte, error) {
+	if token.Id == ETH.Id {
+		return nil, fmt.Errorf("ETH is not an ERC-20 token and needs no approval")
+	}
+	if len(spender) != 20 {
+		return nil, fmt.Errorf("Spender address must be 20 bytes long. len: %d", len(spender))
+	}
+	if amount.Sign() < 0 || amount.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("Amount does not fit in uint256: %s", amount)
+	}
+	calldata := make([]byte, 4+32+32)
+	copy(calldata, approveSelector)
+	copy(calldata[4+12:], spender)
+	amount.FillBytes(calldata[4+32:])
+	return calldata, nil
+}
@@ -0,0 +1,60 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestApproveSelector(t *testing.T) {
+	if got := keccak256([]byte("approve(address,uint256)"))[:4]; !bytes.Equal(got, approveSelector) {
+		t.Errorf("selector %x, want %x", approveSelector, got)
+	}
+}
+
+func TestApproveCalldata(t *testing.T) {
+	usdc := testTokens[1]
+	spender, _ := hex.DecodeString("abcdef0123456789abcdef0123456789abcdef01")
+	got, err := ApproveCalldata(usdc, spender, big.NewInt(1000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// approve(0xabcdef0123456789abcdef0123456789abcdef01, 1000000)
+	want := "095ea7b3" +
+		"000000000000000000000000abcdef0123456789abcdef0123456789abcdef01" +
+		"00000000000000000000000000000000000000000000000000000000000f4240"
+	if hex.EncodeToString(got) != want {
+		t.Errorf("got  %x\nwant %s", got, want)
+	}
+
+	unlimited, err := ApproveCalldata(usdc, spender, maxUint256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tail := hex.EncodeToString(unlimited[4+32:]); tail != strings.Repeat("f", 64) {
+		t.Errorf("max uint256 encoded as %s", tail)
+	}
+}
+
+func TestApproveCalldataErrors(t *testing.T) {
+	spender := make([]byte, 20)
+	usdc := testTokens[1]
+	tests := []struct {
+		name    string
+		token   Token
+		spender []byte
+		amount  *big.Int
+	}{
+		{"ETH", ETH, spender, big.NewInt(1)},
+		{"short spender", usdc, spender[:19], big.NewInt(1)},
+		{"negative amount", usdc, spender, big.NewInt(-1)},
+		{"amount over uint256", usdc, spender, new(big.Int).Lsh(big.NewInt(1), 256)},
+	}
+	for _, tt := range tests {
+		if _, err := ApproveCalldata(tt.token, tt.spender, tt.amount); err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+}
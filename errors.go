@@ -0,0 +1,38 @@
+package zinc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned, possibly wrapped, by the serializers. Use errors.Is to
+// test for them.
+var (
+	ErrAccountIdTooBig = errors.New("AccountId is too big")
+	ErrTokenIdTooBig   = errors.New("TokenId is too big")
+	// ErrAmountNotPackable is returned for amounts and fees that have no
+	// exact packed encoding: negative, too big or too precise.
+	ErrAmountNotPackable = errors.New("Value is not packable")
+	ErrBadAddressPrefix  = errors.New("ETH address must start with '0x' and PubKeyHash must start with 'sync:'")
+	// ErrAddressLength is the error an AddressLengthError unwraps to.
+	ErrAddressLength = errors.New("Address must be 20 bytes long")
+	// ErrTokenUnknown is returned when a TokenResolver cannot resolve a
+	// token, so callers can fall back to showing raw base units.
+	ErrTokenUnknown = errors.New("Unknown token")
+)
+
+// AddressLengthError reports an address that does not decode to 20 bytes.
+type AddressLengthError struct {
+	Length int
+}
+
+func (e *AddressLengthError) Error() string {
+	if e.Length < 20 {
+		return fmt.Sprintf("Address too short: must be 20 bytes long, got %d bytes", e.Length)
+	}
+	return fmt.Sprintf("Address too long: must be 20 bytes long, got %d bytes", e.Length)
+}
+
+func (e *AddressLengthError) Unwrap() error {
+	return ErrAddressLength
+}
@@ -0,0 +1,96 @@
+package zinc
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// hardenedOffset marks a BIP-32 child index as hardened.
+const hardenedOffset = 1 << 31
+
+// ethDerivationPath is the BIP-44 path of the first Ethereum addresses,
+// m/44'/60'/0'/0, to which the account index is appended.
+var ethDerivationPath = []uint32{44 + hardenedOffset, 60 + hardenedOffset, 0 + hardenedOffset, 0}
+
+// deriveEthKey derives the Ethereum key at m/44'/60'/0'/0/index from a BIP-39
+// seed, the same key MetaMask and ethers use for that account index.
+func deriveEthKey(seed []byte, index uint32) (*secp256k1.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	var key secp256k1.ModNScalar
+	if overflow := key.SetByteSlice(sum[:32]); overflow || key.IsZero() {
+		return nil, fmt.Errorf("Invalid master key derived from seed")
+	}
+	chainCode := sum[32:]
+	for _, i := range append(ethDerivationPath, index) {
+		var data []byte
+		if i >= hardenedOffset {
+			k := key.Bytes()
+			data = append([]byte{0}, k[:]...)
+		} else {
+			k := key.Bytes()
+			data = secp256k1.PrivKeyFromBytes(k[:]).PubKey().SerializeCompressed()
+		}
+		var idx [4]byte
+		binary.BigEndian.PutUint32(idx[:], i)
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		mac.Write(idx[:])
+		sum := mac.Sum(nil)
+		var tweak secp256k1.ModNScalar
+		if overflow := tweak.SetByteSlice(sum[:32]); overflow {
+			return nil, fmt.Errorf("Invalid child key at index %d", i)
+		}
+		key.Add(&tweak)
+		if key.IsZero() {
+			return nil, fmt.Errorf("Invalid child key at index %d", i)
+		}
+		chainCode = sum[32:]
+	}
+	k := key.Bytes()
+	return secp256k1.PrivKeyFromBytes(k[:]), nil
+}
+
+// personalMessage returns the bytes that eth_sign / personal_sign hash:
+// "\x19Ethereum Signed Message:\n" + len(msg) in decimal + msg.
+func personalMessage(msg []byte) []byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(msg))
+	return append([]byte(prefix), msg...)
+}
+
+// personalMessageHash is the hash that eth_sign / personal_sign signs: the
+// Keccak-256 of personalMessage(msg).
+func personalMessageHash(msg []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(personalMessage(msg))
+	return h.Sum(nil)
+}
+
+// signPersonalMessage signs msg as personal_sign does.
+func signPersonalMessage(key *secp256k1.PrivateKey, msg []byte) []byte {
+	return signHash(key, personalMessageHash(msg))
+}
+
+// signHash signs a 32-byte hash and returns the 65-byte r || s || v signature
+// with v = 27 or 28. Signing is deterministic (RFC 6979), so the same key and
+// hash always give the same signature.
+func signHash(key *secp256k1.PrivateKey, hash []byte) []byte {
+	compact := ecdsa.SignCompact(key, hash, false)
+	return append(compact[1:], compact[0])
+}
+
+// ethAddress returns the 20-byte Ethereum address of pub: the last 20 bytes
+// of the Keccak-256 hash of its uncompressed coordinates.
+func ethAddress(pub *secp256k1.PublicKey) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(pub.SerializeUncompressed()[1:])
+	return h.Sum(nil)[12:]
+}
@@ -0,0 +1,76 @@
+package zinc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// testMnemonic is the development mnemonic of Hardhat and Foundry, whose
+// derived accounts are widely published.
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestDeriveEthKey(t *testing.T) {
+	tests := []struct {
+		index   uint32
+		key     string
+		address string
+	}{
+		{0, "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", "f39fd6e51aad88f6f4ce6ab8827279cfffb92266"},
+		{1, "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d", "70997970c51812dc3a010c7d01b50e0d17dc79c8"},
+		{2, "5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a", "3c44cdddb6a900fa2b585dd299e03d12fa4293bc"},
+	}
+	seed := bip39.NewSeed(testMnemonic, "")
+	for _, tt := range tests {
+		key, err := deriveEthKey(seed, tt.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key.Serialize()); got != tt.key {
+			t.Errorf("key %d = %s, want %s", tt.index, got, tt.key)
+		}
+		if got := hex.EncodeToString(ethAddress(key.PubKey())); got != tt.address {
+			t.Errorf("address %d = %s, want %s", tt.index, got, tt.address)
+		}
+	}
+}
+
+// hardhatAddress is the address of the first account of testMnemonic.
+const hardhatAddress = "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"
+
+// hardhatSigner returns an EthSigner over the key of account index of
+// testMnemonic.
+func hardhatSigner(t *testing.T, index uint32) *KeystoreSigner {
+	t.Helper()
+	key, err := deriveEthKey(bip39.NewSeed(testMnemonic, ""), index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &KeystoreSigner{key: key}
+}
+
+// recoverPersonalSigner returns the address that personal_signed msg with the
+// hex signature sig.
+func recoverPersonalSigner(t *testing.T, msg []byte, sig string) string {
+	t.Helper()
+	r, s, v, err := ParseEthSignature(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact := append(append([]byte{v}, r[:]...), s[:]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, personalMessageHash(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "0x" + hex.EncodeToString(ethAddress(pub))
+}
+
+func TestPersonalMessageHash(t *testing.T) {
+	// ethers hashMessage("hello").
+	const want = "50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750"
+	if got := hex.EncodeToString(personalMessageHash([]byte("hello"))); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package zinc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BuildTransferEthMessage returns the text an Ethereum wallet signs with
+// personal_sign to authorize a Transfer, as zksync.js builds it:
+//
+//	Transfer 1.5 ETH
+//	To: 0x...
+//	Nonce: 3
+//	Fee: 0.0001 ETH
+//	Account Id: 7
+//
+// Amount and fee are in token units with ethers-style formatting, and the
+// recipient is lowercase. The text must match byte for byte, or the
+// signature will not verify.
+func BuildTransferEthMessage(tx *Tx, tokenSymbol string, decimals int) (string, error) {
+	if tx.Type != TxTypeTransfer {
+		return "", fmt.Errorf("Expected a %s, got %q", TxTypeTransfer, tx.Type)
+	}
+	if decimals < 0 {
+		return "", fmt.Errorf("Negative decimals: %d", decimals)
+	}
+	if err := checkTokenSymbol(tokenSymbol); err != nil {
+		return "", err
+	}
+	if _, err := serializeAddress(tx.To); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Transfer %s %s\nTo: %s\nNonce: %d\nFee: %s %s\nAccount Id: %d",
+		formatUnits(tx.Amount.Value(), decimals), tokenSymbol,
+		strings.ToLower(tx.To),
+		tx.Nonce,
+		formatUnits(tx.Fee.Value(), decimals), tokenSymbol,
+		tx.AccountId,
+	), nil
+}
+
+// TransferEthMessage is BuildTransferEthMessage with the symbol and decimals
+// of tx.Token looked up in resolver. If the token cannot be resolved, for
+// whatever reason, the error wraps ErrTokenUnknown.
+func TransferEthMessage(tx *Tx, resolver TokenResolver) (string, error) {
+	token, err := resolver.TokenById(tx.Token)
+	if err != nil {
+		if !errors.Is(err, ErrTokenUnknown) {
+			err = fmt.Errorf("%w id %d: %v", ErrTokenUnknown, tx.Token, err)
+		}
+		return "", err
+	}
+	return BuildTransferEthMessage(tx, token.Symbol, token.Decimals)
+}
+
+// TransferEthSignPreimage returns the exact bytes whose Keccak-256 an
+// Ethereum wallet signs to authorize a Transfer: the BuildTransferEthMessage
+// text behind the personal_sign prefix "\x19Ethereum Signed Message:\n" and
+// the text's length in decimal.
+func TransferEthSignPreimage(tx *Tx, symbol string, decimals int) ([]byte, error) {
+	msg, err := BuildTransferEthMessage(tx, symbol, decimals)
+	if err != nil {
+		return nil, err
+	}
+	return personalMessage([]byte(msg)), nil
+}
+
+// checkTokenSymbol rejects symbols that are not uppercase alphanumerics, such
+// as "eth" or "ETH\nTo: 0x...", which would change the signed text.
+func checkTokenSymbol(symbol string) error {
+	if symbol == "" {
+		return fmt.Errorf("Missing token symbol")
+	}
+	for _, c := range symbol {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return fmt.Errorf("Invalid token symbol %q: expected uppercase letters and digits", symbol)
+		}
+	}
+	return nil
+}
+
+// ChangePubKeyEthMessage returns the bytes an Ethereum wallet signs with
+// personal_sign to authorize a ChangePubKey with ECDSA auth, as zksync.js
+// getChangePubkeyMessage builds them: newPkHash, nonce, accountId and the
+// 32-byte batch hash, which is zero unless EthAuthData sets one.
+func ChangePubKeyEthMessage(tx *Tx) ([]byte, error) {
+	if tx.Type != TxTypeChangePubKey {
+		return nil, fmt.Errorf("Expected a %s, got %q", TxTypeChangePubKey, tx.Type)
+	}
+	msg := make([]byte, 0, 20+4+4+32)
+	msg, err := appendPubKeyHash(msg, tx.NewPkHash)
+	if err != nil {
+		return nil, err
+	}
+	msg = appendNonce(msg, tx.Nonce)
+	if msg, err = appendAccountId(msg, tx.AccountId); err != nil {
+		return nil, err
+	}
+	batchHash := make([]byte, 32)
+	if tx.EthAuthData != nil && tx.EthAuthData.BatchHash != "" {
+		batchHash, err = hex.DecodeString(strings.TrimPrefix(tx.EthAuthData.BatchHash, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid batchHash: %w", err)
+		}
+		if len(batchHash) != 32 {
+			return nil, fmt.Errorf("batchHash must be 32 bytes long. len: %d", len(batchHash))
+		}
+	}
+	return append(msg, batchHash...), nil
+}
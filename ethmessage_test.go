@@ -0,0 +1,178 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBuildTransferEthMessage(t *testing.T) {
+	tx := testTransfer()
+	// The recipient is signed in lowercase whatever its spelling.
+	tx.To = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	got, err := BuildTransferEthMessage(tx, "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Transfer 1.5 ETH\n" +
+		"To: 0x70997970c51812dc3a010c7d01b50e0d17dc79c8\n" +
+		"Nonce: 12\n" +
+		"Fee: 0.0000375 ETH\n" +
+		"Account Id: 7"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTransferEthSignPreimage(t *testing.T) {
+	tx := testTransfer()
+	tx.To = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	got, err := TransferEthSignPreimage(tx, "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := "Transfer 1.5 ETH\n" +
+		"To: 0x70997970c51812dc3a010c7d01b50e0d17dc79c8\n" +
+		"Nonce: 12\n" +
+		"Fee: 0.0000375 ETH\n" +
+		"Account Id: 7"
+	// The message is 106 bytes, so the length is spelled "106".
+	want := "\x19Ethereum Signed Message:\n106" + msg
+	if string(got) != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	// Keccak-256 of the preimage, computed independently; it is what a
+	// wallet signs for this transfer.
+	const wantHash = "3bad001363112562d2ce2d0006487ff12d9e837b32fd9cb0c50b6184ad88e13a"
+	if h := hex.EncodeToString(keccak256(got)); h != wantHash {
+		t.Errorf("hash %s, want %s", h, wantHash)
+	}
+
+	if _, err := TransferEthSignPreimage(testChangePubKey(), "ETH", 18); err == nil {
+		t.Error("preimage built for a ChangePubKey")
+	}
+}
+
+func TestBuildTransferEthMessageWholeUnits(t *testing.T) {
+	// Whole amounts keep one fractional digit, as ethers formatUnits does.
+	tx := testTransfer()
+	tx.Amount = NewBigInt(big.NewInt(2000000))
+	tx.Fee = NewBigInt(big.NewInt(0))
+	got, err := BuildTransferEthMessage(tx, "USDC", 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Transfer 2.0 USDC\n" +
+		"To: " + testTo + "\n" +
+		"Nonce: 12\n" +
+		"Fee: 0.0 USDC\n" +
+		"Account Id: 7"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBuildTransferEthMessageErrors(t *testing.T) {
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	if _, err := BuildTransferEthMessage(withdraw, "ETH", 18); err == nil {
+		t.Error("Withdraw built into a Transfer message")
+	}
+	if _, err := BuildTransferEthMessage(testTransfer(), "ETH", -1); err == nil {
+		t.Error("negative decimals accepted")
+	}
+	badTo := testTransfer()
+	badTo.To = "0x1234"
+	if _, err := BuildTransferEthMessage(badTo, "ETH", 18); err == nil {
+		t.Error("short recipient accepted")
+	}
+}
+
+func TestBuildTransferEthMessageSymbol(t *testing.T) {
+	for _, symbol := range []string{"ETH", "USDC", "1INCH"} {
+		if _, err := BuildTransferEthMessage(testTransfer(), symbol, 18); err != nil {
+			t.Errorf("%q: %v", symbol, err)
+		}
+	}
+	for _, symbol := range []string{"", "eth", "USD C", "ETH\nTo: 0x0", "WETH.e"} {
+		if _, err := BuildTransferEthMessage(testTransfer(), symbol, 18); err == nil {
+			t.Errorf("%q accepted", symbol)
+		}
+	}
+}
+
+func TestChangePubKeyEthMessage(t *testing.T) {
+	got, err := ChangePubKeyEthMessage(testChangePubKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := concatHex(t,
+		"c7712716b9ef6bd21753c4e91decc351b111c06d", // newPkHash
+		"0000000c",               // nonce
+		"00000007",               // accountId
+		strings.Repeat("00", 32), // batchHash
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %x\nwant %x", got, want)
+	}
+
+	tx := testChangePubKey()
+	tx.EthAuthData = &ChangePubKeyAuthData{Type: ChangePubKeyECDSA, BatchHash: "0x" + strings.Repeat("ab", 32)}
+	got, err = ChangePubKeyEthMessage(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got[28:], bytes.Repeat([]byte{0xab}, 32)) {
+		t.Errorf("batch hash %x, want ab repeated", got[28:])
+	}
+	tx.EthAuthData.BatchHash = "0xabcd"
+	if _, err := ChangePubKeyEthMessage(tx); err == nil {
+		t.Error("2-byte batch hash accepted")
+	}
+	if _, err := ChangePubKeyEthMessage(testTransfer()); err == nil {
+		t.Error("Transfer built into a ChangePubKey message")
+	}
+}
+
+// unavailableResolver fails every lookup as a resolver backed by an
+// unreachable token service would.
+type unavailableResolver struct{}
+
+func (unavailableResolver) TokenById(id uint64) (*Token, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (unavailableResolver) TokenByAddress(address string) (*Token, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (unavailableResolver) TokenBySymbol(symbol string) (*Token, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTransferEthMessage(t *testing.T) {
+	tx := testTransfer()
+	tx.Token = 0
+	got, err := TransferEthMessage(tx, testTokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := BuildTransferEthMessage(tx, "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// testTokens has no token 3.
+	if _, err := TransferEthMessage(testTransfer(), testTokens); !errors.Is(err, ErrTokenUnknown) {
+		t.Errorf("unlisted token: got %v, want ErrTokenUnknown", err)
+	}
+	if _, err := TransferEthMessage(tx, unavailableResolver{}); !errors.Is(err, ErrTokenUnknown) {
+		t.Errorf("unavailable resolver: got %v, want ErrTokenUnknown", err)
+	}
+}
@@ -0,0 +1,103 @@
+package zinc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+const ethSignatureLen = 65
+
+// ParseEthSignature splits a hex encoded 65-byte Ethereum signature into its
+// r, s and v components. A recovery id of 0 or 1 is normalized to 27 or 28.
+func ParseEthSignature(sig string) (r, s [32]byte, v byte, err error) {
+	bytes, err := hex.DecodeString(strings.TrimPrefix(sig, "0x"))
+	if err != nil {
+		return r, s, 0, err
+	}
+	if len(bytes) != ethSignatureLen {
+		return r, s, 0, fmt.Errorf("Ethereum signature must be %d bytes long. len: %d", ethSignatureLen, len(bytes))
+	}
+	copy(r[:], bytes[:32])
+	copy(s[:], bytes[32:64])
+	v = bytes[64]
+	if v < 27 {
+		v += 27
+	}
+	if v != 27 && v != 28 {
+		return r, s, 0, fmt.Errorf("Invalid Ethereum signature recovery id: %d", bytes[64])
+	}
+	return r, s, v, nil
+}
+
+// VerifyEthSignature reports whether the Ethereum signature of a Transfer was
+// made by its sender. It rebuilds the personal_sign message with
+// BuildTransferEthMessage, recovers the signing address and compares it with
+// tx.From. A well-formed signature by anyone else gives false; only malformed
+// input is an error. The signature travels next to the Tx, so this takes the
+// whole Transaction.
+func VerifyEthSignature(txn *Transaction, tokenSymbol string, decimals int) (bool, error) {
+	from, err := serializeAddress(txn.Tx.From)
+	if err != nil {
+		return false, err
+	}
+	signer, err := recoverEthSigner(txn, tokenSymbol, decimals)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(signer, from), nil
+}
+
+// VerifyBatchEthSignature reports whether signature, the r || s || v bytes
+// SignBatch produces, was made by signer over txs: a personal_sign of the
+// SHA-256 of their concatenated serializations. As with VerifyEthSignature,
+// a signature by anyone else gives false and only malformed input is an
+// error.
+func VerifyBatchEthSignature(txs []*Tx, signature []byte, signer string) (bool, error) {
+	want, err := serializeAddress(signer)
+	if err != nil {
+		return false, err
+	}
+	ser, err := (&Batch{Txs: txs}).Serialize()
+	if err != nil {
+		return false, err
+	}
+	hash := sha256.Sum256(ser)
+	got, err := recoverPersonalAddress(hash[:], hex.EncodeToString(signature))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(got, want), nil
+}
+
+// recoverEthSigner returns the address that made the Ethereum signature of a
+// Transfer. A signature that recovers to no valid key yields a nil address.
+func recoverEthSigner(txn *Transaction, tokenSymbol string, decimals int) ([]byte, error) {
+	msg, err := BuildTransferEthMessage(&txn.Tx, tokenSymbol, decimals)
+	if err != nil {
+		return nil, err
+	}
+	return recoverPersonalAddress([]byte(msg), txn.EthSignature.Signature)
+}
+
+// recoverPersonalAddress returns the address that signed msg with
+// personal_sign, or nil if sig recovers to no valid key.
+func recoverPersonalAddress(msg []byte, sig string) ([]byte, error) {
+	r, s, v, err := ParseEthSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	compact := append([]byte{v}, r[:]...)
+	compact = append(compact, s[:]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, personalMessageHash(msg))
+	if err != nil {
+		// The bytes have the right shape but name no valid point, so the
+		// signature cannot be the sender's.
+		return nil, nil
+	}
+	return ethAddress(pub), nil
+}
@@ -0,0 +1,128 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestParseEthSignature(t *testing.T) {
+	rHex, sHex := strings.Repeat("11", 32), strings.Repeat("22", 32)
+	tests := []struct {
+		sig string
+		v   byte
+	}{
+		{"0x" + rHex + sHex + "1b", 27},
+		{"0x" + rHex + sHex + "1c", 28},
+		{rHex + sHex + "1b", 27},
+		// Recovery ids 0 and 1, as some signers return them.
+		{"0x" + rHex + sHex + "00", 27},
+		{"0x" + rHex + sHex + "01", 28},
+	}
+	for _, tt := range tests {
+		r, s, v, err := ParseEthSignature(tt.sig)
+		if err != nil {
+			t.Errorf("%s: %v", tt.sig, err)
+			continue
+		}
+		if !bytes.Equal(r[:], bytes.Repeat([]byte{0x11}, 32)) || !bytes.Equal(s[:], bytes.Repeat([]byte{0x22}, 32)) {
+			t.Errorf("%s: got r %x, s %x", tt.sig, r, s)
+		}
+		if v != tt.v {
+			t.Errorf("%s: v %d, want %d", tt.sig, v, tt.v)
+		}
+	}
+}
+
+func TestParseEthSignatureErrors(t *testing.T) {
+	rs := strings.Repeat("11", 64)
+	for _, sig := range []string{
+		"",
+		"0x" + rs,              // no v
+		"0x" + rs + "1b00",     // 66 bytes
+		"0x" + rs[1:] + "1b",   // odd length
+		"0x" + rs[2:] + "zz1b", // not hex
+		"0x" + rs + "02",       // v out of range
+		"0x" + rs + "1d",       // v out of range
+	} {
+		if _, _, _, err := ParseEthSignature(sig); err == nil {
+			t.Errorf("%q accepted", sig)
+		}
+	}
+}
+
+// testSignedTransfer returns testTransfer from the Hardhat account signed in
+// ETH by the Hardhat account index.
+func testSignedTransfer(t *testing.T, index uint32) *Transaction {
+	t.Helper()
+	tx := testTransfer()
+	tx.From = hardhatAddress
+	msg, err := BuildTransferEthMessage(tx, "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := SignEthMessage([]byte(msg), hardhatSigner(t, index))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Transaction{Tx: *tx, EthSignature: *sig}
+}
+
+func TestVerifyEthSignature(t *testing.T) {
+	txn := testSignedTransfer(t, 0)
+	if ok, err := VerifyEthSignature(txn, "ETH", 18); err != nil || !ok {
+		t.Errorf("sender's signature: got %v, %v, want true", ok, err)
+	}
+	// v as 0/1 instead of 27/28.
+	raw := []byte(txn.EthSignature.Signature)
+	v, err := hex.DecodeString(string(raw[len(raw)-2:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lowV := *txn
+	lowV.EthSignature.Signature = string(raw[:len(raw)-2]) + hex.EncodeToString([]byte{v[0] - 27})
+	if ok, err := VerifyEthSignature(&lowV, "ETH", 18); err != nil || !ok {
+		t.Errorf("recovery id %d: got %v, %v, want true", v[0]-27, ok, err)
+	}
+
+	if ok, err := VerifyEthSignature(testSignedTransfer(t, 1), "ETH", 18); err != nil || ok {
+		t.Errorf("another key's signature: got %v, %v, want false", ok, err)
+	}
+	// The signature covers the message, so the wrong token details fail.
+	if ok, err := VerifyEthSignature(txn, "USDC", 6); err != nil || ok {
+		t.Errorf("other token: got %v, %v, want false", ok, err)
+	}
+	tampered := *txn
+	tampered.Tx.Nonce++
+	if ok, err := VerifyEthSignature(&tampered, "ETH", 18); err != nil || ok {
+		t.Errorf("tampered tx: got %v, %v, want false", ok, err)
+	}
+
+	malformed := *txn
+	malformed.EthSignature.Signature = "0x1234"
+	if _, err := VerifyEthSignature(&malformed, "ETH", 18); err == nil {
+		t.Error("malformed signature verified without an error")
+	}
+}
+
+func TestRecoverEthSigner(t *testing.T) {
+	got, err := recoverEthSigner(testSignedTransfer(t, 1), "ETH", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "70997970c51812dc3a010c7d01b50e0d17dc79c8"; hex.EncodeToString(got) != want {
+		t.Errorf("recovered 0x%x, want 0x%s", got, want)
+	}
+
+	// r above the curve order is well formed but names no point.
+	txn := testSignedTransfer(t, 0)
+	txn.EthSignature.Signature = "0x" + strings.Repeat("ff", 32) + strings.Repeat("00", 31) + "01" + "1b"
+	got, err = recoverEthSigner(txn, "ETH", 18)
+	if err != nil || got != nil {
+		t.Errorf("unrecoverable signature: got %x, %v, want nil, nil", got, err)
+	}
+	if ok, err := VerifyEthSignature(txn, "ETH", 18); err != nil || ok {
+		t.Errorf("unrecoverable signature verified: %v, %v", ok, err)
+	}
+}
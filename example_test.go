@@ -0,0 +1,30 @@
+package zinc_test
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+
+	zinc "github.com/motxx/zinc-sdk-go"
+)
+
+func ExampleSerializeTx() {
+	tx := &zinc.Tx{
+		Type:       zinc.TxTypeTransfer,
+		AccountId:  1,
+		From:       "0x36615cf349d7f6344891b1e7ca7c72883f5dc049",
+		To:         "0x1234567812345678123456781234567812345678",
+		Token:      0,
+		Amount:     zinc.NewBigInt(big.NewInt(1000000000000000000)),
+		Fee:        zinc.NewBigInt(big.NewInt(37500000000000)),
+		Nonce:      2,
+		ValidUntil: 4294967295,
+	}
+	ser, err := zinc.SerializeTx(tx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%x\n", ser)
+	// Output:
+	// 050000000136615cf349d7f6344891b1e7ca7c72883f5dc049123456781234567812345678123456781234567800004a817c80082eeb00000002000000000000000000000000ffffffff
+}
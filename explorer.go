@@ -0,0 +1,114 @@
+package zinc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// explorerTx is the display form of a transaction written by ExplorerJSON.
+// Which fields are present depends on the type.
+type explorerTx struct {
+	Hash               string  `json:"hash"`
+	Type               TxType  `json:"type"`
+	AccountId          *uint64 `json:"accountId,omitempty"`
+	InitiatorAccountId *uint64 `json:"initiatorAccountId,omitempty"`
+	From               string  `json:"from,omitempty"`
+	To                 string  `json:"to,omitempty"`
+	Account            string  `json:"account,omitempty"`
+	NewPkHash          string  `json:"newPkHash,omitempty"`
+	Target             string  `json:"target,omitempty"`
+	Token              string  `json:"token,omitempty"`
+	Amount             string  `json:"amount,omitempty"`
+	FeeToken           string  `json:"feeToken"`
+	Fee                string  `json:"fee"`
+	Nonce              uint64  `json:"nonce"`
+	ValidFrom          string  `json:"validFrom"`
+	ValidUntil         string  `json:"validUntil"`
+}
+
+// ExplorerJSON renders tx for people rather than for signing: amounts and
+// fees in token units next to their token symbols, EIP-55 checksummed
+// addresses and validity bounds as RFC 3339 UTC times. The output is
+// deterministic, so it can be cached or compared. Tokens are looked up in
+// resolver; an unknown token fails with ErrTokenUnknown.
+func (tx *Tx) ExplorerJSON(resolver TokenResolver) ([]byte, error) {
+	hash, err := TxHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	e := explorerTx{
+		Hash:       hash,
+		Type:       tx.Type,
+		Nonce:      tx.Nonce,
+		ValidFrom:  explorerTime(tx.ValidFrom),
+		ValidUntil: explorerTime(tx.ValidUntil),
+	}
+	feeTokenId := tx.Token
+	switch tx.Type {
+	case TxTypeTransfer, TxTypeWithdraw:
+		token, err := resolver.TokenById(tx.Token)
+		if err != nil {
+			return nil, err
+		}
+		e.AccountId = &tx.AccountId
+		if e.From, err = checksumAddress(tx.From); err != nil {
+			return nil, err
+		}
+		if e.To, err = checksumAddress(tx.To); err != nil {
+			return nil, err
+		}
+		e.Token = token.Symbol
+		e.Amount = FormatUnits(tx.Amount.Value(), token.Decimals)
+	case TxTypeChangePubKey:
+		e.AccountId = &tx.AccountId
+		if e.Account, err = checksumAddress(tx.Account); err != nil {
+			return nil, err
+		}
+		e.NewPkHash = tx.NewPkHash
+		feeTokenId = tx.FeeToken
+	case TxTypeForcedExit:
+		token, err := resolver.TokenById(tx.Token)
+		if err != nil {
+			return nil, err
+		}
+		e.InitiatorAccountId = &tx.InitiatorAccountId
+		if e.Target, err = checksumAddress(tx.Target); err != nil {
+			return nil, err
+		}
+		e.Token = token.Symbol
+	default:
+		return nil, fmt.Errorf("Unknown transaction type: %q", tx.Type)
+	}
+	feeToken, err := resolver.TokenById(feeTokenId)
+	if err != nil {
+		return nil, err
+	}
+	e.FeeToken = feeToken.Symbol
+	e.Fee = FormatUnits(tx.Fee.Value(), feeToken.Decimals)
+	return json.Marshal(e)
+}
+
+// checksumAddress returns the EIP-55 spelling of a hex address.
+func checksumAddress(address string) (string, error) {
+	b, err := serializeAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + eip55(hex.EncodeToString(b)), nil
+}
+
+// maxExplorerTime is the last second of year 9999, the latest time RFC 3339
+// can spell.
+const maxExplorerTime = 253402300799
+
+// explorerTime renders a UNIX timestamp in RFC 3339. Bounds past year 9999,
+// such as the common "no expiry" MaxUint64, stay as a number of seconds.
+func explorerTime(ts uint64) string {
+	if ts > maxExplorerTime {
+		return strconv.FormatUint(ts, 10)
+	}
+	return time.Unix(int64(ts), 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,66 @@
+package zinc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExplorerJSON(t *testing.T) {
+	tokens := append(TokenList{{Id: 3, Address: "0x6b175474e89094c44da98b954eedeac495271d0f", Symbol: "DAI", Decimals: 18}}, testTokens...)
+	tx := testTransfer()
+	tx.ValidFrom = 1700000000
+	got, err := tx.ExplorerJSON(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"hash":"sync-tx:` + testTransferExplorerHash + `","type":"Transfer","accountId":7,` +
+		`"from":"0x36615Cf349d7F6344891B1e7CA7C72883F5dc049","to":"0x1234567812345678123456781234567812345678",` +
+		`"token":"DAI","amount":"1.5","feeToken":"DAI","fee":"0.0000375","nonce":12,` +
+		`"validFrom":"2023-11-14T22:13:20Z","validUntil":"2106-02-07T06:28:15Z"}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+// testTransferExplorerHash is the TxHash of testTransfer with ValidFrom
+// 1700000000.
+const testTransferExplorerHash = "0bfb24674b45abc876029624f839b42e08754e84eebb5fdbfc382d2bc3a1402f"
+
+func TestExplorerJSONChangePubKey(t *testing.T) {
+	tx := testChangePubKey()
+	got, err := tx.ExplorerJSON(testTokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fee is in FeeToken, USDC with 6 decimals.
+	const want = `{"hash":"sync-tx:54d0587ef0f0b8c8739da6170699a7bac76ed06b01e90dbdbc901499141b9f84","type":"ChangePubKey","accountId":7,` +
+		`"account":"0x36615Cf349d7F6344891B1e7CA7C72883F5dc049","newPkHash":"sync:c7712716b9ef6bd21753c4e91decc351b111c06d",` +
+		`"feeToken":"USDC","fee":"37500000","nonce":12,"validFrom":"1970-01-01T00:00:00Z","validUntil":"2106-02-07T06:28:15Z"}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestExplorerTime(t *testing.T) {
+	tests := []struct {
+		ts   uint64
+		want string
+	}{
+		{0, "1970-01-01T00:00:00Z"},
+		{4294967295, "2106-02-07T06:28:15Z"},
+		{253402300799, "9999-12-31T23:59:59Z"},
+		{253402300800, "253402300800"},
+		{18446744073709551615, "18446744073709551615"},
+	}
+	for _, tt := range tests {
+		if got := explorerTime(tt.ts); got != tt.want {
+			t.Errorf("%d: got %s, want %s", tt.ts, got, tt.want)
+		}
+	}
+}
+
+func TestExplorerJSONUnknownToken(t *testing.T) {
+	if _, err := testTransfer().ExplorerJSON(testTokens); !errors.Is(err, ErrTokenUnknown) {
+		t.Errorf("got %v, want ErrTokenUnknown", err)
+	}
+}
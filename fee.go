@@ -0,0 +1,90 @@
+package zinc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// txFeeResponse is the part of the get_tx_fee result that is used; the other
+// fields break the total down into gas and proof costs.
+type txFeeResponse struct {
+	TotalFee BigInt `json:"totalFee"`
+}
+
+// rpcFeeType returns the get_tx_fee spelling of txType. A ChangePubKey is
+// priced as authorized by an ECDSA signature, the usual wallet flow.
+func rpcFeeType(txType string) (interface{}, error) {
+	switch txType {
+	case "Transfer", "Withdraw", "FastWithdraw", "ForcedExit":
+		return txType, nil
+	case string(TxTypeChangePubKey):
+		return map[string]ChangePubKeyAuthType{"ChangePubKey": ChangePubKeyECDSA}, nil
+	}
+	return nil, fmt.Errorf("Unknown fee type: %s", txType)
+}
+
+// GetTxFee asks the server with get_tx_fee for the fee of a txType
+// transaction sent from address and paid in tokenSymbol. txType is a
+// transaction type name or "FastWithdraw". The fee is in base units and is
+// rounded down to a packable value if the server returns one that is not.
+func (c *Client) GetTxFee(ctx context.Context, txType string, address string, tokenSymbol string) (*big.Int, error) {
+	return c.txFee(ctx, txType, address, tokenSymbol)
+}
+
+// EstimateFeeMulti returns the fee of a txType transaction sent from address
+// in each of tokens, keyed by token id, as decimal strings in base units.
+// All the get_tx_fee calls go out in a single batched JSON-RPC request, and
+// the fees are rounded down to packable values like GetTxFee does.
+func (c *Client) EstimateFeeMulti(ctx context.Context, txType TxType, address string, tokens []uint64) (map[uint64]string, error) {
+	fees := make(map[uint64]string, len(tokens))
+	if len(tokens) == 0 {
+		return fees, nil
+	}
+	feeType, err := rpcFeeType(string(txType))
+	if err != nil {
+		return nil, err
+	}
+	reqs := make([]rpcRequest, len(tokens))
+	for i, token := range tokens {
+		reqs[i] = c.newRequest("get_tx_fee", []interface{}{feeType, address, token})
+	}
+	res, err := c.callBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range res {
+		if r.Error != nil {
+			return nil, fmt.Errorf("Token %d: %w", tokens[i], r.Error)
+		}
+		var fee txFeeResponse
+		if err := json.Unmarshal(r.Result, &fee); err != nil {
+			return nil, fmt.Errorf("Token %d: %w", tokens[i], err)
+		}
+		closest, _, err := ClosestPackableTransactionFee(fee.TotalFee.Value())
+		if err != nil {
+			return nil, fmt.Errorf("Token %d: %w", tokens[i], err)
+		}
+		fees[tokens[i]] = closest.String()
+	}
+	return fees, nil
+}
+
+// txFee is GetTxFee with token given in any form the node accepts: a token
+// id, an address or a symbol.
+func (c *Client) txFee(ctx context.Context, txType string, address string, token interface{}) (*big.Int, error) {
+	feeType, err := rpcFeeType(txType)
+	if err != nil {
+		return nil, err
+	}
+	var res txFeeResponse
+	if err := c.call(ctx, "get_tx_fee", []interface{}{feeType, address, token}, &res); err != nil {
+		return nil, err
+	}
+	fee, _, err := ClosestPackableTransactionFee(res.TotalFee.Value())
+	if err != nil {
+		return nil, err
+	}
+	return fee, nil
+}
@@ -0,0 +1,150 @@
+package zinc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetTxFee(t *testing.T) {
+	tests := []struct {
+		txType    string
+		wantParam string
+	}{
+		{"Transfer", `"Transfer"`},
+		{"Withdraw", `"Withdraw"`},
+		{"FastWithdraw", `"FastWithdraw"`},
+		{"ForcedExit", `"ForcedExit"`},
+		{"ChangePubKey", `{"ChangePubKey":"ECDSA"}`},
+	}
+	for _, tt := range tests {
+		client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+			if req.Method != "get_tx_fee" {
+				t.Errorf("method %q, want get_tx_fee", req.Method)
+			}
+			if len(req.Params) != 3 {
+				t.Errorf("%d params, want 3", len(req.Params))
+				return nil, &RPCError{Code: -32602, Message: "Invalid params"}
+			}
+			if got := string(req.Params[0]); got != tt.wantParam {
+				t.Errorf("%s: fee type %s, want %s", tt.txType, got, tt.wantParam)
+			}
+			if got := string(req.Params[1]); got != `"`+testFrom+`"` {
+				t.Errorf("%s: address %s, want %q", tt.txType, got, testFrom)
+			}
+			if got := string(req.Params[2]); got != `"ETH"` {
+				t.Errorf("%s: token %s, want \"ETH\"", tt.txType, got)
+			}
+			return json.RawMessage(`{"feeType":"Transfer","gasTxAmount":"1","gasPriceWei":"1","gasFee":"1","zkpFee":"1","totalFee":"37500000000000"}`), nil
+		})
+		fee, err := client.GetTxFee(context.Background(), tt.txType, testFrom, "ETH")
+		if err != nil {
+			t.Errorf("%s: %v", tt.txType, err)
+			continue
+		}
+		if fee.String() != "37500000000000" {
+			t.Errorf("%s: fee %s, want 37500000000000", tt.txType, fee)
+		}
+	}
+}
+
+func TestGetTxFeeRoundsToPackable(t *testing.T) {
+	client := newMockNode(t, func(req testRPCRequest) (interface{}, *RPCError) {
+		return json.RawMessage(`{"totalFee":"37512345678901"}`), nil
+	})
+	fee, err := client.GetTxFee(context.Background(), "Transfer", testFrom, "ETH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fee mantissa has 11 bits, so only the first three digits survive.
+	if fee.String() != "37500000000000" {
+		t.Errorf("fee %s, want 37500000000000", fee)
+	}
+	if _, err := PackFee(fee.String()); err != nil {
+		t.Errorf("returned fee is not packable: %v", err)
+	}
+}
+
+func TestGetTxFeeUnknownType(t *testing.T) {
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for an unknown fee type")
+	})
+	if _, err := client.GetTxFee(context.Background(), "Swap", testFrom, "ETH"); err == nil {
+		t.Error("unknown fee type accepted")
+	}
+}
+
+func TestEstimateFeeMulti(t *testing.T) {
+	// The fee in token 5 is not packable and comes back rounded down.
+	totals := map[uint64]string{0: "37500000000000", 2: "2500000", 5: "123456"}
+	want := map[uint64]string{0: "37500000000000", 2: "2500000", 5: "123400"}
+	var requests int32
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var reqs []testRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Errorf("decoding batch: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		res := make([]testRPCResponse, len(reqs))
+		for i, req := range reqs {
+			if req.Method != "get_tx_fee" || len(req.Params) != 3 {
+				t.Errorf("request %d: %s %s, want get_tx_fee with 3 params", i, req.Method, req.Params)
+				continue
+			}
+			if got := string(req.Params[0]); got != `"Transfer"` {
+				t.Errorf("request %d: fee type %s, want \"Transfer\"", i, got)
+			}
+			var token uint64
+			if err := json.Unmarshal(req.Params[2], &token); err != nil {
+				t.Errorf("request %d: token %s is not an id", i, req.Params[2])
+			}
+			res[len(reqs)-1-i] = testRPCResponse{JSONRPC: "2.0", Id: req.Id, Result: map[string]string{"totalFee": totals[token]}}
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+	got, err := client.EstimateFeeMulti(context.Background(), TxTypeTransfer, testFrom, []uint64{0, 2, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("%d HTTP requests, want 1 batch", n)
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d fees, want %d", len(got), len(want))
+	}
+	for token, fee := range want {
+		if got[token] != fee {
+			t.Errorf("fee in token %d = %q, want %s", token, got[token], fee)
+		}
+	}
+}
+
+func TestEstimateFeeMultiErrors(t *testing.T) {
+	client := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var reqs []testRPCRequest
+		json.NewDecoder(r.Body).Decode(&reqs)
+		res := make([]testRPCResponse, len(reqs))
+		for i, req := range reqs {
+			res[i] = testRPCResponse{JSONRPC: "2.0", Id: req.Id, Result: map[string]string{"totalFee": "1000"}}
+		}
+		res[1] = testRPCResponse{JSONRPC: "2.0", Id: reqs[1].Id, Error: &RPCError{Code: 105, Message: "Chosen token is not suitable for paying fees"}}
+		json.NewEncoder(w).Encode(res)
+	})
+	_, err := client.EstimateFeeMulti(context.Background(), TxTypeTransfer, testFrom, []uint64{0, 9})
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != 105 {
+		t.Errorf("got %v, want the *RPCError of token 9", err)
+	}
+
+	if fees, err := client.EstimateFeeMulti(context.Background(), TxTypeTransfer, testFrom, nil); err != nil || len(fees) != 0 {
+		t.Errorf("no tokens: got %v, %v", fees, err)
+	}
+	if _, err := client.EstimateFeeMulti(context.Background(), "Swap", testFrom, []uint64{0}); err == nil {
+		t.Error("unknown tx type accepted")
+	}
+}
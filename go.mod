@@ -2,4 +2,9 @@ module github.com/motxx/zinc-sdk-go
 
 go 1.16
 
-require github.com/zksync-sdk/zksync-sdk-go v0.0.0-20210422135339-0599ab16d1d7 // indirect
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.0.1
+	github.com/tyler-smith/go-bip39 v1.1.0
+	github.com/zksync-sdk/zksync-sdk-go v0.0.0-20210422135339-0599ab16d1d7
+	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b
+)
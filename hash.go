@@ -0,0 +1,62 @@
+package zinc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// txHashPrefix marks a hex transaction hash as zkSync expects it in the API.
+const txHashPrefix = "sync-tx:"
+
+// TxHash returns the hash under which the server tracks tx: the SHA-256 of its
+// SerializeTx bytes, hex encoded with the "sync-tx:" prefix.
+func TxHash(tx *Tx, opts ...SerializeOption) (string, error) {
+	hash, err := txHashBytes(tx, opts...)
+	if err != nil {
+		return "", err
+	}
+	return txHashPrefix + hex.EncodeToString(hash), nil
+}
+
+// txHashBytes returns the 32 bytes behind TxHash.
+func txHashBytes(tx *Tx, opts ...SerializeOption) ([]byte, error) {
+	h := sha256.New()
+	if _, err := SerializeTxTo(h, tx, opts...); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// BatchChainHash commits to txs in order with a SHA-256 hash chain over
+// their TxHash bytes h1..hn: H(h1) for one transaction, and
+// H(H(...H(h1)||h2)...||hn) in general. It is a lighter commitment than a
+// Merkle root, at the cost of proofs for single transactions.
+func BatchChainHash(txs []*Tx) ([]byte, error) {
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("Batch is empty")
+	}
+	var chain []byte
+	for i, tx := range txs {
+		hash, err := txHashBytes(tx)
+		if err != nil {
+			return nil, fmt.Errorf("Transaction %d: %w", i, err)
+		}
+		link := sha256.Sum256(append(chain, hash...))
+		chain = link[:]
+	}
+	return chain, nil
+}
+
+// shortHashLen is the number of hex characters ShortHash keeps.
+const shortHashLen = 8
+
+// ShortHash returns the first 8 hex characters of the TxHash of tx, without
+// the "sync-tx:" prefix, as a short id for logs.
+func (tx *Tx) ShortHash() (string, error) {
+	hash, err := TxHash(tx)
+	if err != nil {
+		return "", err
+	}
+	return hash[len(txHashPrefix) : len(txHashPrefix)+shortHashLen], nil
+}
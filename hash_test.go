@@ -0,0 +1,102 @@
+package zinc
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestTxHash(t *testing.T) {
+	got, err := TxHash(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SHA-256 of the ProtocolV1 serialization of testTransfer.
+	const want = "sync-tx:88e7ae3e81f255a025507b5e08b1aa844f9c69f43c2203856ea2d7db86c6bd9a"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	again, err := TxHash(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != got {
+		t.Errorf("same transaction hashed to %s and %s", got, again)
+	}
+
+	tx := testTransfer()
+	tx.Nonce++
+	other, err := TxHash(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == got {
+		t.Error("hash did not change with the nonce")
+	}
+}
+
+func TestTxHashInvalid(t *testing.T) {
+	tx := testTransfer()
+	tx.To = "0x1234"
+	if _, err := TxHash(tx); err == nil {
+		t.Error("invalid transaction hashed without an error")
+	}
+}
+
+func TestShortHash(t *testing.T) {
+	tx := testTransfer()
+	got, err := tx.ShortHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := TxHash(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := full[len("sync-tx:") : len("sync-tx:")+8]; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got != "88e7ae3e" {
+		t.Errorf("got %s, want 88e7ae3e", got)
+	}
+
+	bad := testTransfer()
+	bad.To = "0x1234"
+	if _, err := bad.ShortHash(); err == nil {
+		t.Error("short hash of an unserializable tx")
+	}
+}
+
+func TestBatchChainHash(t *testing.T) {
+	// Transfers 0 to 2 of testBatch hash to
+	//   h1 = 398844f4a50059768051a2b188ef83db6bc454b4a78cd89d2afed861fd552b16
+	//   h2 = 1ff2fbfaedca5a2816361227ff34a30a180ef582e62645fe92469e899f6836cd
+	//   h3 = 1629ffab4c81d7d74185d2865a89a987ae33457697c5bfa5469ef757259cb9bf
+	// and the chain, computed independently, is sha256(h1) for one and
+	// sha256(sha256(sha256(h1) || h2) || h3) for three.
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "73d4b2fe46a3fa79fd1384286270423b49589b9e843b3502a3b1aa110c5de9ca"},
+		{3, "0f4e4c6c13bbb7946d1b194e4f5c1c9ffd7f56bf526c098f4bd6b0decf72df55"},
+	}
+	for _, tt := range tests {
+		got, err := BatchChainHash(testBatch(tt.n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("%d transactions: got %x, want %s", tt.n, got, tt.want)
+		}
+	}
+
+	// The chain depends on the order.
+	txs := testBatch(3)
+	txs[0], txs[2] = txs[2], txs[0]
+	if got, err := BatchChainHash(txs); err != nil || hex.EncodeToString(got) == tests[1].want {
+		t.Errorf("reordered batch: got %x, %v", got, err)
+	}
+	if _, err := BatchChainHash(nil); err == nil {
+		t.Error("empty batch hashed")
+	}
+}
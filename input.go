@@ -0,0 +1,44 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// NormalizeInputFile rewrites the contract input JSON at path in canonical
+// form: lowercase addresses, amounts without leading zeros and the current
+// field names. Files written with the old malformed validFrom tag are read
+// back correctly because field names match case-insensitively.
+func NormalizeInputFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var input ContractInput
+	if err := dec.Decode(&input); err != nil {
+		return err
+	}
+	input.Transaction.Tx.normalize()
+	out, err := json.MarshalIndent(&input, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), info.Mode())
+}
+
+// normalize lowercases the addresses of tx. Amounts need no work: they are
+// parsed on decode and always written without leading zeros.
+func (tx *Tx) normalize() {
+	tx.From = strings.ToLower(tx.From)
+	tx.To = strings.ToLower(tx.To)
+	tx.Account = strings.ToLower(tx.Account)
+	tx.Target = strings.ToLower(tx.Target)
+}
@@ -0,0 +1,41 @@
+package zinc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeInputFile(t *testing.T) {
+	tx := testForcedExit()
+	tx.Target = "0xABCDEF78" + testTo[10:]
+	tx.Account = "0xABCDEF5cf349d7f6344891b1e7ca7c72883f5dc049"
+	data, err := json.Marshal(&ContractInput{Transaction: Transaction{Tx: *tx}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NormalizeInputFile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var input ContractInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		t.Fatal(err)
+	}
+	got := input.Transaction.Tx
+	if got.Target != strings.ToLower(tx.Target) {
+		t.Errorf("target %s was not lowercased", got.Target)
+	}
+	if got.Account != strings.ToLower(tx.Account) {
+		t.Errorf("account %s was not lowercased", got.Account)
+	}
+}
@@ -0,0 +1,48 @@
+package zinc
+
+import (
+	"fmt"
+
+	"github.com/tyler-smith/go-bip39"
+	zkscrypto "github.com/zksync-sdk/zksync-sdk-go"
+)
+
+const minSeedLen = 32
+
+// zkSyncSeedMessage is the message zksync.js asks the wallet to sign on
+// mainnet; the signature is the seed of the L2 key.
+const zkSyncSeedMessage = "Access zkSync account.\n\nOnly sign this message for a trusted client!"
+
+// PrivateKeyFromZkSyncSeed derives the L2 signing key from a seed exactly as
+// zksync.js privateKeyFromSeed does. Both call the same zksync-crypto
+// derivation, which needs at least 32 bytes of seed.
+func PrivateKeyFromZkSyncSeed(seed []byte) (*zkscrypto.PrivateKey, error) {
+	if len(seed) < minSeedLen {
+		return nil, fmt.Errorf("Seed must be at least %d bytes long. len: %d", minSeedLen, len(seed))
+	}
+	return zkscrypto.NewPrivateKey(seed)
+}
+
+// NewPrivateKeyFromSeed creates the L2 signing key from a seed of at least 32
+// bytes.
+//
+// Deprecated: Use PrivateKeyFromZkSyncSeed.
+func NewPrivateKeyFromSeed(seed []byte) (*zkscrypto.PrivateKey, error) {
+	return PrivateKeyFromZkSyncSeed(seed)
+}
+
+// NewPrivateKeyFromMnemonic derives the L2 signing key of the Ethereum account
+// at m/44'/60'/0'/0/accountIndex of a BIP-39 mnemonic. Like zksync.js, it signs
+// zkSyncSeedMessage with the Ethereum key and uses the signature as the seed,
+// so the result matches the key a wallet would produce for that account.
+func NewPrivateKeyFromMnemonic(mnemonic string, accountIndex uint32) (*zkscrypto.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("Invalid mnemonic: not a BIP-39 English phrase with a valid checksum")
+	}
+	ethKey, err := deriveEthKey(seed, accountIndex)
+	if err != nil {
+		return nil, err
+	}
+	return PrivateKeyFromZkSyncSeed(signPersonalMessage(ethKey, []byte(zkSyncSeedMessage)))
+}
@@ -0,0 +1,82 @@
+package zinc
+
+import "testing"
+
+// The zero-seed vector of zksync-crypto, the library that both zkscrypto and
+// zksync.js privateKeyFromSeed run.
+const (
+	zeroSeedPrivateKey = "011f5b99084c5c2e2d5e63488e0f7168d599a5c01fe9fec4c99605743da5e85c"
+	zeroSeedPublicKey  = "179c3a59147d30316c886628852348c9b42a18b821084ac9ef79bd73e9b94e8d"
+)
+
+func TestPrivateKeyFromZkSyncSeed(t *testing.T) {
+	key, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := key.HexString(); got != zeroSeedPrivateKey {
+		t.Errorf("private key %s, want %s", got, zeroSeedPrivateKey)
+	}
+	pub, err := key.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pub.HexString(); got != zeroSeedPublicKey {
+		t.Errorf("public key %s, want %s", got, zeroSeedPublicKey)
+	}
+}
+
+func TestPrivateKeyFromZkSyncSeedShort(t *testing.T) {
+	if _, err := PrivateKeyFromZkSyncSeed(make([]byte, 31)); err == nil {
+		t.Fatal("31-byte seed accepted")
+	}
+}
+
+func TestNewPrivateKeyFromSeed(t *testing.T) {
+	key, err := NewPrivateKeyFromSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.HexString() != want.HexString() {
+		t.Errorf("key %s, want %s", key.HexString(), want.HexString())
+	}
+	if _, err := NewPrivateKeyFromSeed(make([]byte, 31)); err == nil {
+		t.Error("31-byte seed accepted")
+	}
+	// Longer seeds, such as a 65-byte wallet signature, are accepted like
+	// PrivateKeyFromZkSyncSeed accepts them.
+	if _, err := NewPrivateKeyFromSeed(make([]byte, 33)); err != nil {
+		t.Errorf("33-byte seed: %v", err)
+	}
+}
+
+func TestNewPrivateKeyFromMnemonic(t *testing.T) {
+	key, err := NewPrivateKeyFromMnemonic(testMnemonic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := NewPrivateKeyFromMnemonic(testMnemonic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.HexString() != again.HexString() {
+		t.Errorf("same mnemonic gave %s and %s", key.HexString(), again.HexString())
+	}
+}
+
+func TestNewPrivateKeyFromMnemonicInvalid(t *testing.T) {
+	for _, mnemonic := range []string{
+		"",
+		// Bad checksum: the last word of testMnemonic is "junk".
+		"test test test test test test test test test test test test",
+		"test test test test test test test test test test test notaword",
+	} {
+		if _, err := NewPrivateKeyFromMnemonic(mnemonic, 0); err == nil {
+			t.Errorf("mnemonic %q accepted", mnemonic)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package zinc
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+// KeystoreSigner is an EthSigner over a key decrypted from an Ethereum
+// keystore (Web3 Secret Storage version 3) file, as written by geth, MetaMask
+// and ethers.
+type KeystoreSigner struct {
+	key *secp256k1.PrivateKey
+}
+
+type keystoreFile struct {
+	Version int `json:"version"`
+	Crypto  struct {
+		Cipher       string `json:"cipher"`
+		CipherText   string `json:"ciphertext"`
+		CipherParams struct {
+			IV string `json:"iv"`
+		} `json:"cipherparams"`
+		KDF       string `json:"kdf"`
+		KDFParams struct {
+			DKLen int    `json:"dklen"`
+			Salt  string `json:"salt"`
+			// scrypt
+			N int `json:"n"`
+			R int `json:"r"`
+			P int `json:"p"`
+			// pbkdf2
+			C   int    `json:"c"`
+			PRF string `json:"prf"`
+		} `json:"kdfparams"`
+		MAC string `json:"mac"`
+	} `json:"crypto"`
+}
+
+// NewKeystoreSigner decrypts keystoreJSON with passphrase.
+func NewKeystoreSigner(keystoreJSON []byte, passphrase string) (*KeystoreSigner, error) {
+	var ks keystoreFile
+	if err := json.Unmarshal(keystoreJSON, &ks); err != nil {
+		return nil, err
+	}
+	if ks.Version != 3 {
+		return nil, fmt.Errorf("Unsupported keystore version: %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("Unsupported keystore cipher: %s", ks.Crypto.Cipher)
+	}
+	salt, err := hex.DecodeString(ks.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid keystore salt: %s", err)
+	}
+	params := ks.Crypto.KDFParams
+	if params.DKLen < 32 {
+		return nil, fmt.Errorf("Keystore derived key must be at least 32 bytes long. dklen: %d", params.DKLen)
+	}
+	var derived []byte
+	switch ks.Crypto.KDF {
+	case "scrypt":
+		derived, err = scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+		if err != nil {
+			return nil, err
+		}
+	case "pbkdf2":
+		if params.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("Unsupported keystore pbkdf2 prf: %s", params.PRF)
+		}
+		derived = pbkdf2.Key([]byte(passphrase), salt, params.C, params.DKLen, sha256.New)
+	default:
+		return nil, fmt.Errorf("Unsupported keystore kdf: %s", ks.Crypto.KDF)
+	}
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid keystore ciphertext: %s", err)
+	}
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid keystore mac: %s", err)
+	}
+	h := sha3.NewLegacyKeccak256()
+	h.Write(derived[16:32])
+	h.Write(cipherText)
+	if !bytes.Equal(h.Sum(nil), mac) {
+		return nil, fmt.Errorf("Wrong keystore passphrase")
+	}
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid keystore iv: %s", err)
+	}
+	block, err := aes.NewCipher(derived[:16])
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("Keystore iv must be %d bytes long. len: %d", block.BlockSize(), len(iv))
+	}
+	key := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(key, cipherText)
+	if len(key) != 32 {
+		return nil, fmt.Errorf("Keystore private key must be 32 bytes long. len: %d", len(key))
+	}
+	return &KeystoreSigner{key: secp256k1.PrivKeyFromBytes(key)}, nil
+}
+
+// SignHash signs a 32-byte hash.
+func (s *KeystoreSigner) SignHash(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("Hash must be 32 bytes long. len: %d", len(hash))
+	}
+	return signHash(s.key, hash), nil
+}
+
+// Address returns the Ethereum address of the key.
+func (s *KeystoreSigner) Address() string {
+	return "0x" + hex.EncodeToString(ethAddress(s.key.PubKey()))
+}
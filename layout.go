@@ -0,0 +1,144 @@
+package zinc
+
+import "fmt"
+
+// transferLength is the serialized length of a Transfer: type, accountId,
+// from, to, token, packed amount, packed fee, nonce, validFrom, validUntil.
+const transferLength = 1 + 4 + 20 + 20 + 2 + packedAmountSize + packedFeeSize + 4 + 8 + 8
+
+// withdrawLength is the serialized length of a Withdraw, which carries the
+// amount unpacked.
+const withdrawLength = 1 + 4 + 20 + 20 + 2 + fullAmountSize + packedFeeSize + 4 + 8 + 8
+
+// changePubKeyLength is the serialized length of a ChangePubKey: type,
+// accountId, account, newPkHash, feeToken, packed fee, nonce, validFrom,
+// validUntil.
+const changePubKeyLength = 1 + 4 + 20 + 20 + 2 + packedFeeSize + 4 + 8 + 8
+
+// forcedExitLength is the serialized length of a ForcedExit: type,
+// initiatorAccountId, target, token, packed fee, nonce, validFrom, validUntil.
+const forcedExitLength = 1 + 4 + 20 + 2 + packedFeeSize + 4 + 8 + 8
+
+// txLengths holds the serialized length of each supported transaction type in
+// the DefaultProtocolVersion layout.
+var txLengths = map[TxType]int{
+	TxTypeWithdraw:     withdrawLength,
+	TxTypeTransfer:     transferLength,
+	TxTypeChangePubKey: changePubKeyLength,
+	TxTypeForcedExit:   forcedExitLength,
+}
+
+func txLength(t TxType) (int, error) {
+	n, ok := txLengths[t]
+	if !ok {
+		return 0, fmt.Errorf("Unknown transaction type: %s", t)
+	}
+	return n, nil
+}
+
+func maxTxLength() int {
+	max := 0
+	for _, n := range txLengths {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+// PadToMaxSize right-pads a serialized transaction with zero bytes to the
+// length of the largest transaction type.
+func PadToMaxSize(data []byte) ([]byte, error) {
+	max := maxTxLength()
+	if len(data) > max {
+		return nil, fmt.Errorf("Serialized transaction is %d bytes, longer than the maximum %d", len(data), max)
+	}
+	padded := make([]byte, max)
+	copy(padded, data)
+	return padded, nil
+}
+
+// ValidateLength reports an error unless data is exactly the serialized length
+// of a txType transaction.
+func ValidateLength(txType TxType, data []byte) error {
+	n, err := txLength(txType)
+	if err != nil {
+		return err
+	}
+	if len(data) < n {
+		return fmt.Errorf("Serialized %s too short: expected %d bytes, got %d", txType, n, len(data))
+	}
+	if len(data) > n {
+		return fmt.Errorf("Serialized %s too long: expected %d bytes, got %d", txType, n, len(data))
+	}
+	return nil
+}
+
+// TransferFieldChunks splits the serialization of a transfer into chunks of
+// fieldBytes bytes, zero-padding the last chunk, for tools that consume
+// transactions as field elements.
+func TransferFieldChunks(tx *Tx, fieldBytes int) ([][]byte, error) {
+	if fieldBytes <= 0 {
+		return nil, fmt.Errorf("Field size must be positive: %d", fieldBytes)
+	}
+	ser, err := SerializeTransfer(tx)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make([][]byte, 0, (len(ser)+fieldBytes-1)/fieldBytes)
+	for i := 0; i < len(ser); i += fieldBytes {
+		chunk := make([]byte, fieldBytes)
+		copy(chunk, ser[i:])
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// HasAppendedSignature reports whether data is a serialized txType transaction
+// followed by a 64-byte L2 signature, rather than the bare serialization.
+func HasAppendedSignature(data []byte, txType TxType) bool {
+	n, err := txLength(txType)
+	if err != nil {
+		return false
+	}
+	return len(data) == n+l2SignatureLen
+}
+
+// TxsPerBlock returns how many txType transactions fit in byteBudget bytes of
+// block space.
+func TxsPerBlock(byteBudget int, txType TxType) (int, error) {
+	if byteBudget < 0 {
+		return 0, fmt.Errorf("Byte budget must not be negative: %d", byteBudget)
+	}
+	n, err := txLength(txType)
+	if err != nil {
+		return 0, err
+	}
+	return byteBudget / n, nil
+}
+
+// transferFieldSizes are the sizes of the fields of a serialized Transfer in
+// the default layout, in order: type, accountId, from, to, token, packed
+// amount, packed fee, nonce, validFrom, validUntil.
+var transferFieldSizes = []int{1, 4, 20, 20, 2, packedAmountSize, packedFeeSize, 4, 8, 8}
+
+// paddedSlotSize is the width of each field slot in SerializeTransferPadded.
+const paddedSlotSize = 32
+
+// SerializeTransferPadded serializes a transfer with each field right-aligned
+// in its own 32-byte slot, for verifiers that read fields as words. The field
+// encodings are those of SerializeTransfer; only the layout differs.
+func SerializeTransferPadded(tx *Tx) ([]byte, error) {
+	ser, err := SerializeTransfer(tx)
+	if err != nil {
+		return nil, err
+	}
+	padded := make([]byte, len(transferFieldSizes)*paddedSlotSize)
+	offset := 0
+	for i, n := range transferFieldSizes {
+		slotEnd := (i + 1) * paddedSlotSize
+		copy(padded[slotEnd-n:slotEnd], ser[offset:offset+n])
+		offset += n
+	}
+	return padded, nil
+}
@@ -0,0 +1,195 @@
+package zinc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTxsPerBlock(t *testing.T) {
+	tests := []struct {
+		budget int
+		txType TxType
+		want   int
+	}{
+		// A Transfer is 74 bytes.
+		{1000, TxTypeTransfer, 13},
+		{74, TxTypeTransfer, 1},
+		{73, TxTypeTransfer, 0},
+		{0, TxTypeTransfer, 0},
+		// A Withdraw carries its amount unpacked: 85 bytes.
+		{1000, TxTypeWithdraw, 11},
+	}
+	for _, tt := range tests {
+		got, err := TxsPerBlock(tt.budget, tt.txType)
+		if err != nil {
+			t.Errorf("TxsPerBlock(%d, %s): %v", tt.budget, tt.txType, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("TxsPerBlock(%d, %s) = %d, want %d", tt.budget, tt.txType, got, tt.want)
+		}
+	}
+	if transferLength != 74 {
+		t.Errorf("transferLength = %d, want 74", transferLength)
+	}
+}
+
+func TestTxsPerBlockErrors(t *testing.T) {
+	if _, err := TxsPerBlock(-1, TxTypeTransfer); err == nil {
+		t.Error("negative budget accepted")
+	}
+	if _, err := TxsPerBlock(1000, "Swap"); err == nil {
+		t.Error("unknown type accepted")
+	}
+}
+
+func TestSerializeTransferPadded(t *testing.T) {
+	got, err := SerializeTransferPadded(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{
+		"05",       // type
+		"00000007", // accountId
+		"36615cf349d7f6344891b1e7ca7c72883f5dc049", // from
+		"1234567812345678123456781234567812345678", // to
+		"0003",             // token
+		"6fc23ac008",       // packed amount
+		"2eeb",             // packed fee
+		"0000000c",         // nonce
+		"0000000000000000", // validFrom
+		"00000000ffffffff", // validUntil
+	}
+	if len(got) != len(fields)*32 {
+		t.Fatalf("length %d, want %d slots of 32 bytes", len(got), len(fields))
+	}
+	for i, field := range fields {
+		want := concatHex(t, field)
+		slot := got[i*32 : (i+1)*32]
+		pad := slot[:32-len(want)]
+		if !bytes.Equal(pad, make([]byte, len(pad))) {
+			t.Errorf("slot %d: padding %x is not zero", i, pad)
+		}
+		if value := slot[32-len(want):]; !bytes.Equal(value, want) {
+			t.Errorf("slot %d: right-aligned value %x, want %x", i, value, want)
+		}
+	}
+}
+
+func TestSerializeTransferPaddedInvalid(t *testing.T) {
+	tx := testTransfer()
+	tx.To = "0x1234"
+	if _, err := SerializeTransferPadded(tx); err == nil {
+		t.Error("invalid transfer serialized without an error")
+	}
+}
+
+func TestPadToMaxSize(t *testing.T) {
+	ser, err := SerializeTx(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	padded, err := PadToMaxSize(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Withdraw, with its unpacked amount, is the largest type.
+	if len(padded) != withdrawLength || withdrawLength != 85 {
+		t.Fatalf("padded to %d bytes, want 85", len(padded))
+	}
+	if !bytes.Equal(padded[:len(ser)], ser) {
+		t.Error("padding changed the serialization")
+	}
+	if !bytes.Equal(padded[len(ser):], make([]byte, 85-len(ser))) {
+		t.Errorf("padding is not zero: %x", padded[len(ser):])
+	}
+
+	if padded, err := PadToMaxSize(make([]byte, 85)); err != nil || len(padded) != 85 {
+		t.Errorf("maximum-size input: got %d bytes, %v", len(padded), err)
+	}
+	if _, err := PadToMaxSize(make([]byte, 86)); err == nil {
+		t.Error("86-byte input padded")
+	}
+}
+
+func TestValidateLength(t *testing.T) {
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	for _, tx := range []*Tx{testTransfer(), withdraw, testChangePubKey(), testForcedExit()} {
+		ser, err := SerializeTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateLength(tx.Type, ser); err != nil {
+			t.Errorf("%s: %v", tx.Type, err)
+		}
+		if err := ValidateLength(tx.Type, ser[:len(ser)-1]); err == nil {
+			t.Errorf("%s: short buffer accepted", tx.Type)
+		}
+		if err := ValidateLength(tx.Type, append(ser, 0)); err == nil {
+			t.Errorf("%s: long buffer accepted", tx.Type)
+		}
+	}
+	if err := ValidateLength("Swap", make([]byte, transferLength)); err == nil {
+		t.Error("unknown type accepted")
+	}
+}
+
+func TestTransferFieldChunks(t *testing.T) {
+	ser, err := SerializeTransfer(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 74 bytes in 31-byte field elements: 31 + 31 + 12 with 19 bytes of
+	// padding.
+	chunks, err := TransferFieldChunks(testTransfer(), 31)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("%d chunks, want 3", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len(chunk) != 31 {
+			t.Errorf("chunk %d is %d bytes, want 31", i, len(chunk))
+		}
+	}
+	if got := bytes.Join(chunks, nil); !bytes.Equal(got[:len(ser)], ser) {
+		t.Errorf("chunks %x do not start with the serialization %x", got, ser)
+	}
+	if tail := chunks[2][12:]; !bytes.Equal(tail, make([]byte, 19)) {
+		t.Errorf("padding is %x, want 19 zero bytes", tail)
+	}
+
+	// A size that divides the serialization needs no padding.
+	if chunks, err := TransferFieldChunks(testTransfer(), 37); err != nil || len(chunks) != 2 {
+		t.Errorf("37-byte fields: got %d chunks, %v, want 2", len(chunks), err)
+	}
+	if _, err := TransferFieldChunks(testTransfer(), 0); err == nil {
+		t.Error("zero field size accepted")
+	}
+}
+
+func TestHasAppendedSignature(t *testing.T) {
+	ser, err := SerializeTx(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := append(append([]byte(nil), ser...), make([]byte, 64)...)
+	if HasAppendedSignature(ser, TxTypeTransfer) {
+		t.Error("bare serialization reported as signed")
+	}
+	if !HasAppendedSignature(signed, TxTypeTransfer) {
+		t.Error("serialization with a signature reported as unsigned")
+	}
+	if HasAppendedSignature(signed[:len(signed)-1], TxTypeTransfer) {
+		t.Error("truncated signature reported as signed")
+	}
+	// The lengths are per type.
+	if HasAppendedSignature(signed, TxTypeWithdraw) {
+		t.Error("signed Transfer reported as a signed Withdraw")
+	}
+	if HasAppendedSignature(signed, "Swap") {
+		t.Error("unknown type reported as signed")
+	}
+}
@@ -0,0 +1,87 @@
+package zinc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// Early versions of SerializeTransfer wrote no type byte and copied the
+// amount and fee into the output as ASCII decimal digits instead of packing
+// them. The fixed-size fields around them were laid out as today.
+const (
+	legacyTransferHeadLen = 4 + 20 + 20 + 2 // accountId, from, to, token
+	legacyTransferTailLen = 4 + 8 + 8       // nonce, validFrom, validUntil
+)
+
+// DeserializeLegacyTransfer recovers a Transfer from bytes written by the old
+// serializer that stored amount and fee as ASCII. The two numbers were written
+// back to back, so the split between them is inferred: only splits where both
+// are well-formed decimals and the amount and fee are packable are considered,
+// since the server would not have accepted anything else. It errors if no
+// split or more than one split qualifies.
+func DeserializeLegacyTransfer(data []byte) (*Tx, error) {
+	if len(data) < legacyTransferHeadLen+legacyTransferTailLen+2 {
+		return nil, fmt.Errorf("Legacy transfer too short: %d bytes", len(data))
+	}
+	head := data[:legacyTransferHeadLen]
+	digits := string(data[legacyTransferHeadLen : len(data)-legacyTransferTailLen])
+	tail := data[len(data)-legacyTransferTailLen:]
+
+	var amount, fee BigInt
+	candidates := 0
+	for i := 1; i < len(digits); i++ {
+		a, f, ok := legacyAmountSplit(digits[:i], digits[i:])
+		if !ok {
+			continue
+		}
+		amount, fee = a, f
+		candidates++
+	}
+	switch {
+	case candidates == 0:
+		return nil, fmt.Errorf("Legacy transfer amount and fee are not valid: %q", digits)
+	case candidates > 1:
+		return nil, fmt.Errorf("Legacy transfer amount and fee are ambiguous: %q has %d possible splits", digits, candidates)
+	}
+
+	return &Tx{
+		Type:       TxTypeTransfer,
+		AccountId:  uint64(binary.BigEndian.Uint32(head[0:4])),
+		From:       "0x" + hex.EncodeToString(head[4:24]),
+		To:         "0x" + hex.EncodeToString(head[24:44]),
+		Token:      uint64(binary.BigEndian.Uint16(head[44:46])),
+		Amount:     amount,
+		Fee:        fee,
+		Nonce:      uint64(binary.BigEndian.Uint32(tail[0:4])),
+		ValidFrom:  binary.BigEndian.Uint64(tail[4:12]),
+		ValidUntil: binary.BigEndian.Uint64(tail[12:20]),
+	}, nil
+}
+
+// legacyAmountSplit reports whether amount and fee are a plausible reading of
+// the legacy ASCII fields.
+func legacyAmountSplit(amount, fee string) (BigInt, BigInt, bool) {
+	if hasLeadingZero(amount) || hasLeadingZero(fee) {
+		return BigInt{}, BigInt{}, false
+	}
+	a, err := parseDecimal(amount)
+	if err != nil {
+		return BigInt{}, BigInt{}, false
+	}
+	f, err := parseDecimal(fee)
+	if err != nil {
+		return BigInt{}, BigInt{}, false
+	}
+	if _, err := packAmount(a); err != nil {
+		return BigInt{}, BigInt{}, false
+	}
+	if _, err := packFee(f); err != nil {
+		return BigInt{}, BigInt{}, false
+	}
+	return NewBigInt(a), NewBigInt(f), true
+}
+
+func hasLeadingZero(s string) bool {
+	return len(s) > 1 && s[0] == '0'
+}
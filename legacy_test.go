@@ -0,0 +1,52 @@
+package zinc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// legacyBlob returns a transfer as the old serializer wrote it: no type byte
+// and the amount and fee as ASCII digits.
+func legacyBlob(t *testing.T, digits string) []byte {
+	t.Helper()
+	head := concatHex(t,
+		"00000007", // accountId
+		"36615cf349d7f6344891b1e7ca7c72883f5dc049", // from
+		"1234567812345678123456781234567812345678", // to
+		"0003", // token
+	)
+	tail := concatHex(t,
+		"0000000c",         // nonce
+		"0000000000000000", // validFrom
+		"00000000ffffffff", // validUntil
+	)
+	return append(append(head, digits...), tail...)
+}
+
+func TestDeserializeLegacyTransfer(t *testing.T) {
+	got, err := DeserializeLegacyTransfer(legacyBlob(t, "1500000000000000000"+"37500000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(testTransfer())
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("got  %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func TestDeserializeLegacyTransferAmbiguous(t *testing.T) {
+	// "110" reads as 1 and 10 or as 11 and 0.
+	if _, err := DeserializeLegacyTransfer(legacyBlob(t, "110")); err == nil {
+		t.Fatal("ambiguous amount and fee decoded without an error")
+	}
+}
+
+func TestDeserializeLegacyTransferInvalid(t *testing.T) {
+	if _, err := DeserializeLegacyTransfer(legacyBlob(t, "1x")); err == nil {
+		t.Error("non-digit amount decoded without an error")
+	}
+	if _, err := DeserializeLegacyTransfer(make([]byte, 20)); err == nil {
+		t.Error("short blob decoded without an error")
+	}
+}
@@ -0,0 +1,218 @@
+package zinc
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Bit widths of the packed decimal floats. Amounts and fees share a 5-bit
+// exponent; amounts have a 35-bit mantissa and fees an 11-bit one.
+const (
+	amountExpBits      = 5
+	amountMantissaBits = 35
+	feeExpBits         = 5
+	feeMantissaBits    = 11
+)
+
+var ten = big.NewInt(10)
+
+// packFloat encodes value as mantissa * 10^exponent, stored big-endian with
+// the mantissa in the high bits and the exponent in the low expBits bits.
+// Like zksync.js it uses the smallest exponent whose mantissa fits, so every
+// packable value has a single encoding.
+// https://github.com/matter-labs/zksync/blob/master/sdk/zksync.js/src/utils.ts
+func packFloat(value *big.Int, expBits, mantissaBits uint) ([]byte, error) {
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s is negative", ErrAmountNotPackable, value)
+	}
+	maxMantissa := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), mantissaBits), big.NewInt(1))
+	maxExponent := uint64(1)<<expBits - 1
+	mantissa := new(big.Int).Set(value)
+	exponent := uint64(0)
+	rem := new(big.Int)
+	for mantissa.Cmp(maxMantissa) > 0 {
+		if exponent == maxExponent {
+			return nil, fmt.Errorf("%w: %s is too big", ErrAmountNotPackable, value)
+		}
+		mantissa.QuoRem(mantissa, ten, rem)
+		if rem.Sign() != 0 {
+			return nil, fmt.Errorf("%w: %s", ErrAmountNotPackable, value)
+		}
+		exponent++
+	}
+	packed := new(big.Int).Lsh(mantissa, expBits)
+	packed.Or(packed, new(big.Int).SetUint64(exponent))
+	return packed.FillBytes(make([]byte, (expBits+mantissaBits)/8)), nil
+}
+
+// appendPacked appends the packFloat encoding of value to dst. Values that fit
+// in a uint64, which covers almost every real amount and fee, are packed
+// without allocating.
+func appendPacked(dst []byte, value *big.Int, expBits, mantissaBits uint) ([]byte, error) {
+	if !value.IsUint64() {
+		packed, err := packFloat(value, expBits, mantissaBits)
+		if err != nil {
+			return nil, err
+		}
+		return append(dst, packed...), nil
+	}
+	maxMantissa := uint64(1)<<mantissaBits - 1
+	maxExponent := uint64(1)<<expBits - 1
+	mantissa := value.Uint64()
+	exponent := uint64(0)
+	for mantissa > maxMantissa {
+		if exponent == maxExponent {
+			return nil, fmt.Errorf("%w: %s is too big", ErrAmountNotPackable, value)
+		}
+		if mantissa%10 != 0 {
+			return nil, fmt.Errorf("%w: %s", ErrAmountNotPackable, value)
+		}
+		mantissa /= 10
+		exponent++
+	}
+	return appendUint(dst, mantissa<<expBits|exponent, int(expBits+mantissaBits)/8), nil
+}
+
+func packAmount(amount *big.Int) ([]byte, error) {
+	return packFloat(amount, amountExpBits, amountMantissaBits)
+}
+
+// PackAmount packs a decimal amount string into the 5-byte transaction amount
+// encoding. It errors if the amount cannot be represented exactly.
+func PackAmount(amount string) ([]byte, error) {
+	value, err := parseDecimal(amount)
+	if err != nil {
+		return nil, err
+	}
+	return packAmount(value)
+}
+
+func packFee(fee *big.Int) ([]byte, error) {
+	return packFloat(fee, feeExpBits, feeMantissaBits)
+}
+
+// PackFee packs a decimal fee string into the 2-byte transaction fee
+// encoding. It errors if the fee cannot be represented exactly.
+func PackFee(fee string) ([]byte, error) {
+	value, err := parseDecimal(fee)
+	if err != nil {
+		return nil, err
+	}
+	return packFee(value)
+}
+
+// unpackFloat is the inverse of packFloat.
+func unpackFloat(packed []byte, expBits uint) *big.Int {
+	value := new(big.Int).SetBytes(packed)
+	exponent := new(big.Int).And(value, big.NewInt(1<<expBits-1))
+	mantissa := value.Rsh(value, expBits)
+	return mantissa.Mul(mantissa, new(big.Int).Exp(ten, exponent, nil))
+}
+
+// decodePackedAmount decodes a 5-byte packed amount.
+func decodePackedAmount(packed []byte) (*big.Int, error) {
+	if len(packed) != packedAmountSize {
+		return nil, fmt.Errorf("Packed amount must be %d bytes long. len: %d", packedAmountSize, len(packed))
+	}
+	return unpackFloat(packed, amountExpBits), nil
+}
+
+// decodePackedFee decodes a 2-byte packed fee.
+func decodePackedFee(packed []byte) (*big.Int, error) {
+	if len(packed) != packedFeeSize {
+		return nil, fmt.Errorf("Packed fee must be %d bytes long. len: %d", packedFeeSize, len(packed))
+	}
+	return unpackFloat(packed, feeExpBits), nil
+}
+
+// closestPackable rounds value down to the nearest value packFloat can encode
+// with the given bit widths.
+func closestPackable(value *big.Int, expBits, mantissaBits uint) (*big.Int, error) {
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s is negative", ErrAmountNotPackable, value)
+	}
+	maxMantissa := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), mantissaBits), big.NewInt(1))
+	maxExponent := uint64(1)<<expBits - 1
+	mantissa := new(big.Int).Set(value)
+	exponent := uint64(0)
+	for mantissa.Cmp(maxMantissa) > 0 {
+		if exponent == maxExponent {
+			return nil, fmt.Errorf("%w: %s is too big", ErrAmountNotPackable, value)
+		}
+		mantissa.Quo(mantissa, ten)
+		exponent++
+	}
+	scale := new(big.Int).Exp(ten, new(big.Int).SetUint64(exponent), nil)
+	return mantissa.Mul(mantissa, scale), nil
+}
+
+// PackingDust returns how much of amount is lost by rounding it down to the
+// closest packable transaction amount. It is zero for packable amounts.
+func PackingDust(amount string) (*big.Int, error) {
+	value, err := parseDecimal(amount)
+	if err != nil {
+		return nil, err
+	}
+	closest, err := closestPackable(value, amountExpBits, amountMantissaBits)
+	if err != nil {
+		return nil, err
+	}
+	return value.Sub(value, closest), nil
+}
+
+// AuditResult reports how an amount fares under transaction amount packing.
+type AuditResult struct {
+	Amount   string
+	Packable bool
+	// Closest is the closest packable amount not above Amount.
+	Closest *big.Int
+	// Dust is Amount - Closest.
+	Dust *big.Int
+}
+
+// AuditAmounts reports for each amount whether it is packable and what it
+// would round down to, to reconcile amounts recorded before packing was
+// implemented.
+func AuditAmounts(amounts []string) ([]AuditResult, error) {
+	results := make([]AuditResult, 0, len(amounts))
+	for _, amount := range amounts {
+		value, err := parseDecimal(amount)
+		if err != nil {
+			return nil, err
+		}
+		closest, err := closestPackable(value, amountExpBits, amountMantissaBits)
+		if err != nil {
+			return nil, err
+		}
+		dust := new(big.Int).Sub(value, closest)
+		results = append(results, AuditResult{
+			Amount:   amount,
+			Packable: dust.Sign() == 0,
+			Closest:  closest,
+			Dust:     dust,
+		})
+	}
+	return results, nil
+}
+
+// ClosestPackableTransactionAmount rounds amount down to the closest value
+// that can be packed as a transaction amount, like the zksync.js function of
+// the same name. packable reports whether amount was already packable.
+func ClosestPackableTransactionAmount(amount *big.Int) (closest *big.Int, packable bool, err error) {
+	closest, err = closestPackable(amount, amountExpBits, amountMantissaBits)
+	if err != nil {
+		return nil, false, err
+	}
+	return closest, closest.Cmp(amount) == 0, nil
+}
+
+// ClosestPackableTransactionFee rounds fee down to the closest value that can
+// be packed as a transaction fee, like the zksync.js function of the same
+// name. packable reports whether fee was already packable.
+func ClosestPackableTransactionFee(fee *big.Int) (closest *big.Int, packable bool, err error) {
+	closest, err = closestPackable(fee, feeExpBits, feeMantissaBits)
+	if err != nil {
+		return nil, false, err
+	}
+	return closest, closest.Cmp(fee) == 0, nil
+}
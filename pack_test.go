@@ -0,0 +1,247 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func mustDecimal(t *testing.T, s string) *big.Int {
+	t.Helper()
+	value, err := parseDecimal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+// Packed values are mantissa << 5 | exponent with the smallest exponent whose
+// mantissa fits, as in zksync.js.
+var packAmountTests = []struct {
+	amount string
+	packed string
+}{
+	{"0", "0000000000"},
+	{"1", "0000000020"},
+	// The canonical form of 100 is mantissa 100, exponent 0, not
+	// mantissa 1, exponent 2.
+	{"100", "0000000c80"},
+	{"1500000000000000000", "6fc23ac008"},
+	// 2^35 - 1, the largest mantissa.
+	{"34359738367", "ffffffffe0"},
+	{"343597383670", "ffffffffe1"},
+	{"34359738367" + strings.Repeat("0", 31), "ffffffffff"},
+}
+
+var packFeeTests = []struct {
+	fee    string
+	packed string
+}{
+	{"0", "0000"},
+	{"1000", "7d00"},
+	{"1000000", "7d03"},
+	// The fee in data/input.json of the zkSync examples.
+	{"37500000000000", "2eeb"},
+	// 2^11 - 1, the largest mantissa.
+	{"2047", "ffe0"},
+	{"20470", "ffe1"},
+	{"2047" + strings.Repeat("0", 31), "ffff"},
+}
+
+func TestPackAmount(t *testing.T) {
+	for _, tt := range packAmountTests {
+		got, err := PackAmount(tt.amount)
+		if err != nil {
+			t.Errorf("PackAmount(%s): %v", tt.amount, err)
+			continue
+		}
+		if hex.EncodeToString(got) != tt.packed {
+			t.Errorf("PackAmount(%s) = %x, want %s", tt.amount, got, tt.packed)
+		}
+		appended, err := appendPacked(nil, mustDecimal(t, tt.amount), amountExpBits, amountMantissaBits)
+		if err != nil || !bytes.Equal(appended, got) {
+			t.Errorf("appendPacked(%s) = %x, %v, want %s", tt.amount, appended, err, tt.packed)
+		}
+	}
+}
+
+func TestDecodePackedAmount(t *testing.T) {
+	for _, tt := range packAmountTests {
+		packed, _ := hex.DecodeString(tt.packed)
+		got, err := decodePackedAmount(packed)
+		if err != nil {
+			t.Errorf("decodePackedAmount(%s): %v", tt.packed, err)
+			continue
+		}
+		if got.String() != tt.amount {
+			t.Errorf("decodePackedAmount(%s) = %s, want %s", tt.packed, got, tt.amount)
+		}
+	}
+	// Non-canonical encodings still decode to their value.
+	got, err := decodePackedAmount([]byte{0, 0, 0, 0, 0x22})
+	if err != nil || got.Int64() != 100 {
+		t.Errorf("mantissa 1, exponent 2 decoded to %v, %v, want 100", got, err)
+	}
+	if _, err := decodePackedAmount(make([]byte, 4)); err == nil {
+		t.Error("4-byte packed amount decoded without an error")
+	}
+}
+
+func TestPackFee(t *testing.T) {
+	for _, tt := range packFeeTests {
+		got, err := PackFee(tt.fee)
+		if err != nil {
+			t.Errorf("PackFee(%s): %v", tt.fee, err)
+			continue
+		}
+		if hex.EncodeToString(got) != tt.packed {
+			t.Errorf("PackFee(%s) = %x, want %s", tt.fee, got, tt.packed)
+		}
+		appended, err := appendPacked(nil, mustDecimal(t, tt.fee), feeExpBits, feeMantissaBits)
+		if err != nil || !bytes.Equal(appended, got) {
+			t.Errorf("appendPacked(%s) = %x, %v, want %s", tt.fee, appended, err, tt.packed)
+		}
+	}
+}
+
+func TestDecodePackedFee(t *testing.T) {
+	for _, tt := range packFeeTests {
+		packed, _ := hex.DecodeString(tt.packed)
+		got, err := decodePackedFee(packed)
+		if err != nil {
+			t.Errorf("decodePackedFee(%s): %v", tt.packed, err)
+			continue
+		}
+		if got.String() != tt.fee {
+			t.Errorf("decodePackedFee(%s) = %s, want %s", tt.packed, got, tt.fee)
+		}
+	}
+	if _, err := decodePackedFee(make([]byte, 3)); err == nil {
+		t.Error("3-byte packed fee decoded without an error")
+	}
+}
+
+func TestPackNotPackable(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		pack  func(string) ([]byte, error)
+	}{
+		{"amount too precise", "34359738368", PackAmount},
+		{"amount too big", "34359738367" + strings.Repeat("0", 32), PackAmount},
+		{"fee too precise", "2048", PackFee},
+		{"fee too precise for uint64 path", "12345678901234567", PackFee},
+		{"fee too big", "2047" + strings.Repeat("0", 32), PackFee},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.pack(tt.value)
+			if !errors.Is(err, ErrAmountNotPackable) {
+				t.Errorf("got %v, want ErrAmountNotPackable", err)
+			}
+		})
+	}
+}
+
+func TestPackingDust(t *testing.T) {
+	tests := []struct {
+		amount string
+		dust   string
+	}{
+		{"0", "0"},
+		{"1500000000000000000", "0"},
+		{"34359738367", "0"},
+		// 2^35 needs one more mantissa bit, so the last digit is lost.
+		{"34359738368", "8"},
+		{"123456789012345678901", "2345678901"},
+	}
+	for _, tt := range tests {
+		got, err := PackingDust(tt.amount)
+		if err != nil {
+			t.Errorf("PackingDust(%s): %v", tt.amount, err)
+			continue
+		}
+		if got.String() != tt.dust {
+			t.Errorf("PackingDust(%s) = %s, want %s", tt.amount, got, tt.dust)
+		}
+	}
+	if _, err := PackingDust("-1"); err == nil {
+		t.Error("negative amount accepted")
+	}
+}
+
+func TestAuditAmounts(t *testing.T) {
+	amounts := []string{"0", "1500000000000000000", "34359738368", "123456789012345678901"}
+	want := []struct {
+		packable bool
+		closest  string
+		dust     string
+	}{
+		{true, "0", "0"},
+		{true, "1500000000000000000", "0"},
+		{false, "34359738360", "8"},
+		{false, "123456789010000000000", "2345678901"},
+	}
+	got, err := AuditAmounts(amounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d results, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		r := got[i]
+		if r.Amount != amounts[i] || r.Packable != w.packable || r.Closest.String() != w.closest || r.Dust.String() != w.dust {
+			t.Errorf("%s: got packable %v, closest %s, dust %s, want %v, %s, %s",
+				amounts[i], r.Packable, r.Closest, r.Dust, w.packable, w.closest, w.dust)
+		}
+	}
+
+	if _, err := AuditAmounts([]string{"1", "1.5"}); err == nil {
+		t.Error("non-integer amount audited")
+	}
+}
+
+func TestClosestPackable(t *testing.T) {
+	tests := []struct {
+		value          string
+		amount, fee    string
+		amountPackable bool
+		feePackable    bool
+	}{
+		{"0", "0", "0", true, true},
+		{"37500000000000", "37500000000000", "37500000000000", true, true},
+		{"2048", "2048", "2040", true, false},
+		// The 11-bit fee mantissa rounds far more coarsely than the 35-bit
+		// amount mantissa.
+		{"34359738368", "34359738360", "34300000000", false, false},
+	}
+	for _, tt := range tests {
+		value := mustDecimal(t, tt.value)
+		amount, packable, err := ClosestPackableTransactionAmount(value)
+		if err != nil {
+			t.Errorf("amount %s: %v", tt.value, err)
+		} else if amount.String() != tt.amount || packable != tt.amountPackable {
+			t.Errorf("amount %s: got %s, %v, want %s, %v", tt.value, amount, packable, tt.amount, tt.amountPackable)
+		}
+		fee, packable, err := ClosestPackableTransactionFee(value)
+		if err != nil {
+			t.Errorf("fee %s: %v", tt.value, err)
+		} else if fee.String() != tt.fee || packable != tt.feePackable {
+			t.Errorf("fee %s: got %s, %v, want %s, %v", tt.value, fee, packable, tt.fee, tt.feePackable)
+		}
+		if value.String() != tt.value {
+			t.Errorf("input changed to %s", value)
+		}
+	}
+
+	if _, _, err := ClosestPackableTransactionAmount(big.NewInt(-1)); !errors.Is(err, ErrAmountNotPackable) {
+		t.Errorf("negative amount: got %v, want ErrAmountNotPackable", err)
+	}
+	if _, _, err := ClosestPackableTransactionFee(mustDecimal(t, "2047"+strings.Repeat("0", 32))); !errors.Is(err, ErrAmountNotPackable) {
+		t.Errorf("fee too big: got %v, want ErrAmountNotPackable", err)
+	}
+}
@@ -0,0 +1,50 @@
+package zinc
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const paymentURIScheme = "zksync"
+
+// ParsePaymentURI builds a Transfer from a payment request URI such as
+// zksync:0xADDR?amount=1.5&token=ETH. The amount is in token units and is
+// converted with the token's decimals; token defaults to ETH and amount may
+// be left out. Only To, Token and Amount are set; the payer fills in the rest.
+func ParsePaymentURI(uri string, resolver TokenResolver) (*Tx, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid payment URI: %w", err)
+	}
+	if u.Scheme != paymentURIScheme {
+		return nil, fmt.Errorf("Invalid payment URI: scheme must be %q, got %q", paymentURIScheme, u.Scheme)
+	}
+	if u.Opaque == "" {
+		return nil, fmt.Errorf("Invalid payment URI: missing address")
+	}
+	if _, err := serializeAddress(u.Opaque); err != nil {
+		return nil, fmt.Errorf("Invalid payment URI: %w", err)
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid payment URI: %w", err)
+	}
+	token := &ETH
+	if symbol := query.Get("token"); symbol != "" {
+		token, err = resolver.TokenBySymbol(symbol)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tx := &Tx{
+		Type:  TxTypeTransfer,
+		To:    u.Opaque,
+		Token: token.Id,
+	}
+	if amount := query.Get("amount"); amount != "" {
+		if err := tx.SetAmountDecimal(amount, token.Decimals); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
@@ -0,0 +1,58 @@
+package zinc
+
+import "testing"
+
+var testTokens = TokenList{
+	ETH,
+	{Id: 2, Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: 6},
+}
+
+func TestParsePaymentURI(t *testing.T) {
+	tests := []struct {
+		uri    string
+		token  uint64
+		amount string
+	}{
+		{"zksync:" + testTo + "?amount=1.5&token=ETH", 0, "1500000000000000000"},
+		{"zksync:" + testTo + "?amount=1.5&token=USDC", 2, "1500000"},
+		// token defaults to ETH.
+		{"zksync:" + testTo + "?amount=.5", 0, "500000000000000000"},
+		// amount is optional.
+		{"zksync:" + testTo + "?token=USDC", 2, ""},
+	}
+	for _, tt := range tests {
+		tx, err := ParsePaymentURI(tt.uri, testTokens)
+		if err != nil {
+			t.Errorf("%s: %v", tt.uri, err)
+			continue
+		}
+		if tx.Type != TxTypeTransfer || tx.To != testTo || tx.Token != tt.token {
+			t.Errorf("%s: got type %s, to %s, token %d", tt.uri, tx.Type, tx.To, tx.Token)
+		}
+		if tt.amount == "" {
+			if tx.Amount.Int != nil {
+				t.Errorf("%s: amount %s, want none", tt.uri, tx.Amount)
+			}
+		} else if got := tx.Amount.String(); got != tt.amount {
+			t.Errorf("%s: amount %s, want %s", tt.uri, got, tt.amount)
+		}
+	}
+}
+
+func TestParsePaymentURIMalformed(t *testing.T) {
+	for _, uri := range []string{
+		"",
+		"ethereum:" + testTo + "?amount=1",
+		"zksync:?amount=1",
+		"zksync:0x1234?amount=1",
+		"zksync:" + testTo + "?amount=abc",
+		"zksync:" + testTo + "?amount=1.5%zz",
+		// More fractional digits than USDC has.
+		"zksync:" + testTo + "?amount=0.0000001&token=USDC",
+		"zksync:" + testTo + "?token=DOGE",
+	} {
+		if _, err := ParsePaymentURI(uri, testTokens); err == nil {
+			t.Errorf("%q parsed without an error", uri)
+		}
+	}
+}
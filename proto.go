@@ -0,0 +1,223 @@
+package zinc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// Protobuf wire types used by tx.proto.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// MarshalProto encodes tx as the Tx message in tx.proto. It is meant for
+// compact storage of parsed transactions, not for signing.
+func (tx *Tx) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = appendProtoString(b, 1, string(tx.Type))
+	b = appendProtoVarint(b, 2, tx.AccountId)
+	b = appendProtoString(b, 3, tx.From)
+	b = appendProtoString(b, 4, tx.To)
+	b = appendProtoVarint(b, 5, tx.Token)
+	b = appendProtoBytes(b, 6, tx.Amount.Value().Bytes())
+	b = appendProtoBytes(b, 7, tx.Fee.Value().Bytes())
+	b = appendProtoVarint(b, 8, tx.Nonce)
+	if tx.Signature != (Signature{}) {
+		var sig []byte
+		sig = appendProtoString(sig, 1, tx.Signature.PubKey)
+		sig = appendProtoString(sig, 2, tx.Signature.Signature)
+		b = appendProtoField(b, 9, sig)
+	}
+	b = appendProtoVarint(b, 10, tx.ValidFrom)
+	b = appendProtoVarint(b, 11, tx.ValidUntil)
+	b = appendProtoBytes(b, 12, tx.Memo)
+	b = appendProtoString(b, 13, tx.Account)
+	b = appendProtoString(b, 14, tx.NewPkHash)
+	b = appendProtoVarint(b, 15, tx.FeeToken)
+	if auth := tx.EthAuthData; auth != nil {
+		var a []byte
+		a = appendProtoString(a, 1, string(auth.Type))
+		a = appendProtoString(a, 2, auth.EthSignature)
+		a = appendProtoString(a, 3, auth.BatchHash)
+		a = appendProtoString(a, 4, auth.CreatorAddress)
+		a = appendProtoString(a, 5, auth.SaltArg)
+		a = appendProtoString(a, 6, auth.CodeHash)
+		b = appendProtoField(b, 16, a)
+	}
+	b = appendProtoVarint(b, 17, tx.InitiatorAccountId)
+	b = appendProtoString(b, 18, tx.Target)
+	return b, nil
+}
+
+// UnmarshalProto decodes a Tx message written by MarshalProto into tx.
+// Unknown fields are skipped.
+func (tx *Tx) UnmarshalProto(data []byte) error {
+	var decoded Tx
+	var amount, fee []byte
+	err := readProtoFields(data, func(field uint64, v uint64, b []byte) error {
+		switch field {
+		case 1:
+			decoded.Type = TxType(b)
+		case 2:
+			decoded.AccountId = v
+		case 3:
+			decoded.From = string(b)
+		case 4:
+			decoded.To = string(b)
+		case 5:
+			decoded.Token = v
+		case 6:
+			amount = b
+		case 7:
+			fee = b
+		case 8:
+			decoded.Nonce = v
+		case 9:
+			return readProtoFields(b, func(field uint64, v uint64, b []byte) error {
+				switch field {
+				case 1:
+					decoded.Signature.PubKey = string(b)
+				case 2:
+					decoded.Signature.Signature = string(b)
+				}
+				return nil
+			})
+		case 10:
+			decoded.ValidFrom = v
+		case 11:
+			decoded.ValidUntil = v
+		case 12:
+			decoded.Memo = append([]byte(nil), b...)
+		case 13:
+			decoded.Account = string(b)
+		case 14:
+			decoded.NewPkHash = string(b)
+		case 15:
+			decoded.FeeToken = v
+		case 16:
+			auth := &ChangePubKeyAuthData{}
+			decoded.EthAuthData = auth
+			return readProtoFields(b, func(field uint64, v uint64, b []byte) error {
+				switch field {
+				case 1:
+					auth.Type = ChangePubKeyAuthType(b)
+				case 2:
+					auth.EthSignature = string(b)
+				case 3:
+					auth.BatchHash = string(b)
+				case 4:
+					auth.CreatorAddress = string(b)
+				case 5:
+					auth.SaltArg = string(b)
+				case 6:
+					auth.CodeHash = string(b)
+				}
+				return nil
+			})
+		case 17:
+			decoded.InitiatorAccountId = v
+		case 18:
+			decoded.Target = string(b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	decoded.Amount = NewBigInt(new(big.Int).SetBytes(amount))
+	decoded.Fee = NewBigInt(new(big.Int).SetBytes(fee))
+	*tx = decoded
+	return nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendProtoTag(b []byte, field int, wireType int) []byte {
+	return appendUvarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+// appendProtoVarint, appendProtoBytes and appendProtoString leave out zero
+// values, as proto3 does.
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendProtoTag(b, field, protoVarint)
+	return appendUvarint(b, v)
+}
+
+func appendProtoBytes(b []byte, field int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	return appendProtoField(b, field, v)
+}
+
+func appendProtoString(b []byte, field int, v string) []byte {
+	return appendProtoBytes(b, field, []byte(v))
+}
+
+// appendProtoField writes a length-delimited field even if v is empty, which
+// is how a set but empty sub-message is told apart from an unset one.
+func appendProtoField(b []byte, field int, v []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// readProtoFields calls fn for each field in data with its varint value or
+// its length-delimited payload. Fixed-width fields are skipped.
+func readProtoFields(data []byte, fn func(field uint64, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("Invalid protobuf: bad field tag")
+		}
+		data = data[n:]
+		field, wireType := tag>>3, tag&7
+		if field == 0 {
+			return fmt.Errorf("Invalid protobuf: field number 0")
+		}
+		switch wireType {
+		case protoVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("Invalid protobuf: bad varint in field %d", field)
+			}
+			data = data[n:]
+			if err := fn(field, v, nil); err != nil {
+				return err
+			}
+		case protoBytes:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || l > uint64(len(data)-n) {
+				return fmt.Errorf("Invalid protobuf: bad length in field %d", field)
+			}
+			b := data[n : n+int(l)]
+			data = data[n+int(l):]
+			if err := fn(field, 0, b); err != nil {
+				return err
+			}
+		case protoFixed64, protoFixed32:
+			size := 8
+			if wireType == protoFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return fmt.Errorf("Invalid protobuf: truncated field %d", field)
+			}
+			data = data[size:]
+		default:
+			return fmt.Errorf("Invalid protobuf: unsupported wire type %d in field %d", wireType, field)
+		}
+	}
+	return nil
+}
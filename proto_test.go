@@ -0,0 +1,66 @@
+package zinc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	transfer := testTransfer()
+	transfer.Signature = *testL2Signature()
+	transfer.Memo = []byte("invoice 42")
+	cpk := testChangePubKey()
+	cpk.EthAuthData = &ChangePubKeyAuthData{
+		Type:           ChangePubKeyCREATE2,
+		CreatorAddress: testTo,
+		SaltArg:        "0x01",
+		CodeHash:       "0x02",
+	}
+	for _, tx := range []*Tx{transfer, cpk, testForcedExit()} {
+		t.Run(string(tx.Type), func(t *testing.T) {
+			data, err := tx.MarshalProto()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got Tx
+			if err := got.UnmarshalProto(data); err != nil {
+				t.Fatal(err)
+			}
+			gotJSON, _ := json.Marshal(&got)
+			wantJSON, _ := json.Marshal(tx)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("round trip\ngot  %s\nwant %s", gotJSON, wantJSON)
+			}
+			if len(data) >= len(wantJSON) {
+				t.Errorf("proto is %d bytes, not smaller than the %d bytes of JSON", len(data), len(wantJSON))
+			}
+		})
+	}
+}
+
+func TestUnmarshalProtoSkipsUnknownFields(t *testing.T) {
+	data, err := testTransfer().MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = appendProtoVarint(data, 99, 1)
+	data = appendProtoString(data, 100, "future")
+	var got Tx
+	if err := got.UnmarshalProto(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.Nonce != testTransfer().Nonce {
+		t.Errorf("nonce %d, want %d", got.Nonce, testTransfer().Nonce)
+	}
+}
+
+func TestUnmarshalProtoTruncated(t *testing.T) {
+	data, err := testTransfer().MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Tx
+	if err := got.UnmarshalProto(data[:len(data)-1]); err == nil {
+		t.Fatal("truncated message decoded without an error")
+	}
+}
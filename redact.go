@@ -0,0 +1,36 @@
+package zinc
+
+// Redacted returns a copy of tx that is safe to log: addresses and the new
+// pubKeyHash keep only their first and last few characters (0x36615c...dc049)
+// and signatures are truncated.
+func (tx *Tx) Redacted() *Tx {
+	r := *tx
+	r.From = maskAddress(tx.From)
+	r.To = maskAddress(tx.To)
+	r.Account = maskAddress(tx.Account)
+	r.NewPkHash = maskAddress(tx.NewPkHash)
+	r.Target = maskAddress(tx.Target)
+	r.Signature.Signature = truncate(tx.Signature.Signature, 8)
+	if tx.EthAuthData != nil {
+		auth := *tx.EthAuthData
+		auth.EthSignature = truncate(auth.EthSignature, 8)
+		auth.CreatorAddress = maskAddress(auth.CreatorAddress)
+		r.EthAuthData = &auth
+	}
+	return &r
+}
+
+func maskAddress(address string) string {
+	const head, tail = 8, 5
+	if len(address) <= head+tail {
+		return address
+	}
+	return address[:head] + "..." + address[len(address)-tail:]
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,40 @@
+package zinc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	cpk := testChangePubKey()
+	cpk.EthAuthData = &ChangePubKeyAuthData{
+		Type:         ChangePubKeyECDSA,
+		EthSignature: "0x" + strings.Repeat("ab", ethSignatureLen),
+	}
+	cpk.Signature = *testL2Signature()
+	fe := testForcedExit()
+
+	r := cpk.Redacted()
+	for name, got := range map[string]string{
+		"account":      r.Account,
+		"newPkHash":    r.NewPkHash,
+		"signature":    r.Signature.Signature,
+		"ethSignature": r.EthAuthData.EthSignature,
+		"target":       fe.Redacted().Target,
+	} {
+		if !strings.Contains(got, "...") || len(got) > 16 {
+			t.Errorf("%s not redacted: %s", name, got)
+		}
+	}
+	if got := r.Account; got != "0x36615c...dc049" {
+		t.Errorf("account redacted to %s, want 0x36615c...dc049", got)
+	}
+
+	// The original keeps its values, including the shared auth data.
+	if cpk.Account != testFrom {
+		t.Errorf("Redacted changed the account to %s", cpk.Account)
+	}
+	if strings.Contains(cpk.EthAuthData.EthSignature, "...") {
+		t.Errorf("Redacted changed the auth data of the original")
+	}
+}
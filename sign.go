@@ -0,0 +1,73 @@
+package zinc
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	zkscrypto "github.com/zksync-sdk/zksync-sdk-go"
+)
+
+// Signer produces L2 musig Schnorr signatures. *zkscrypto.PrivateKey
+// implements it; an offline signer can implement it over its own key store.
+type Signer interface {
+	Sign(message []byte) (*zkscrypto.Signature, error)
+	PublicKey() (*zkscrypto.PublicKey, error)
+}
+
+// SignMessage signs msg, typically a serialization produced elsewhere, and
+// returns the signature together with the signer's public key.
+func SignMessage(msg []byte, signer Signer) (*Signature, error) {
+	signature, err := signer.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := signer.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{
+		PubKey:    publicKey.HexString(),
+		Signature: signature.HexString(),
+	}, nil
+}
+
+// SignTx serializes tx with SerializeTx and signs the bytes with privateKey.
+// The result can be put back on tx with AttachSignature.
+func SignTx(tx *Tx, privateKey *zkscrypto.PrivateKey, opts ...SerializeOption) (*Signature, error) {
+	msg, err := SerializeTx(tx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return SignMessage(msg, privateKey)
+}
+
+// AttachSignature sets sig as the L2 signature of tx.
+func (tx *Tx) AttachSignature(sig *Signature) {
+	tx.Signature = *sig
+}
+
+// EthSigner produces L1 Ethereum signatures over a 32-byte hash, returning
+// r || s || v. Hardware wallets implement it with their own signing call so
+// the key never leaves the device; KeystoreSigner implements it over a
+// keystore file.
+type EthSigner interface {
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// SignEthMessage signs msg, such as the text from BuildTransferEthMessage,
+// the way personal_sign does: the hash passed to signer is the Keccak-256 of
+// msg behind the "\x19Ethereum Signed Message:\n" prefix.
+func SignEthMessage(msg []byte, signer EthSigner) (*EthereumSignature, error) {
+	sig, err := signer.SignHash(personalMessageHash(msg))
+	if err != nil {
+		return nil, err
+	}
+	encoded := "0x" + hex.EncodeToString(sig)
+	if _, _, _, err := ParseEthSignature(encoded); err != nil {
+		return nil, fmt.Errorf("EthSigner returned an invalid signature: %w", err)
+	}
+	return &EthereumSignature{
+		Type:      ethSignatureType,
+		Signature: encoded,
+	}, nil
+}
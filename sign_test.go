@@ -0,0 +1,102 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSignTx(t *testing.T) {
+	key, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := key.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := testTransfer()
+	sig, err := SignTx(tx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.PubKey != pub.HexString() {
+		t.Errorf("pubKey %s, want the key's public key %s", sig.PubKey, pub.HexString())
+	}
+	if b, err := hex.DecodeString(sig.Signature); err != nil || len(b) != l2SignatureLen {
+		t.Errorf("signature %q is not %d hex bytes", sig.Signature, l2SignatureLen)
+	}
+
+	tx.AttachSignature(sig)
+	if tx.Signature != *sig {
+		t.Errorf("attached %+v, want %+v", tx.Signature, *sig)
+	}
+	if _, err := AssembleTransaction(tx, &tx.Signature, nil); err == nil {
+		t.Error("a Transfer assembled without an Ethereum signature")
+	}
+}
+
+func TestSignTxInvalid(t *testing.T) {
+	key, err := PrivateKeyFromZkSyncSeed(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := testTransfer()
+	tx.AccountId = MAX_NUMBER_OF_ACCOUNTS
+	if _, err := SignTx(tx, key); err == nil {
+		t.Fatal("signed a tx that does not serialize")
+	}
+}
+
+// mockEthSigner stands in for a hardware wallet: it records the hash it was
+// asked to sign and returns a canned signature.
+type mockEthSigner struct {
+	sig  []byte
+	err  error
+	hash []byte
+}
+
+func (m *mockEthSigner) SignHash(hash []byte) ([]byte, error) {
+	m.hash = append([]byte(nil), hash...)
+	return m.sig, m.err
+}
+
+// cannedEthSignature is r = 0x11..., s = 0x22..., v = 27.
+var cannedEthSignature = append(append(bytes.Repeat([]byte{0x11}, 32), bytes.Repeat([]byte{0x22}, 32)...), 27)
+
+func TestSignEthMessage(t *testing.T) {
+	signer := &mockEthSigner{sig: cannedEthSignature}
+	got, err := SignEthMessage([]byte("hello"), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// personal_sign hash of "hello", as ethers hashMessage computes it.
+	const wantHash = "50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750"
+	if hex.EncodeToString(signer.hash) != wantHash {
+		t.Errorf("signer got hash %x, want %s", signer.hash, wantHash)
+	}
+	want := EthereumSignature{
+		Type:      "EthereumSignature",
+		Signature: "0x" + strings.Repeat("11", 32) + strings.Repeat("22", 32) + "1b",
+	}
+	if *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+}
+
+func TestSignEthMessageSignerErrors(t *testing.T) {
+	errDenied := errors.New("denied on device")
+	if _, err := SignEthMessage([]byte("hello"), &mockEthSigner{err: errDenied}); !errors.Is(err, errDenied) {
+		t.Errorf("got %v, want the signer's error", err)
+	}
+	short := &mockEthSigner{sig: cannedEthSignature[:64]}
+	if _, err := SignEthMessage([]byte("hello"), short); err == nil {
+		t.Error("64-byte signature accepted")
+	}
+	badV := &mockEthSigner{sig: append(append([]byte(nil), cannedEthSignature[:64]...), 5)}
+	if _, err := SignEthMessage([]byte("hello"), badV); err == nil {
+		t.Error("recovery id 5 accepted")
+	}
+}
@@ -0,0 +1,85 @@
+package zinc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Token describes a token registered on the zkSync network.
+type Token struct {
+	Id       uint64 `json:"id"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// ETH is the native token. It is registered with id 0 and the zero address.
+var ETH = Token{
+	Id:       0,
+	Address:  "0x0000000000000000000000000000000000000000",
+	Symbol:   "ETH",
+	Decimals: 18,
+}
+
+// TokenResolver looks up registered tokens.
+type TokenResolver interface {
+	TokenById(id uint64) (*Token, error)
+	TokenByAddress(address string) (*Token, error)
+	TokenBySymbol(symbol string) (*Token, error)
+}
+
+// TokenList is a TokenResolver over a fixed set of tokens. Its lookups fail
+// with ErrTokenUnknown.
+type TokenList []Token
+
+func (l TokenList) TokenById(id uint64) (*Token, error) {
+	for i := range l {
+		if l[i].Id == id {
+			return &l[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w id: %d", ErrTokenUnknown, id)
+}
+
+// TokenByAddress matches addresses case-insensitively.
+func (l TokenList) TokenByAddress(address string) (*Token, error) {
+	for i := range l {
+		if strings.EqualFold(l[i].Address, address) {
+			return &l[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w address: %s", ErrTokenUnknown, address)
+}
+
+func (l TokenList) TokenBySymbol(symbol string) (*Token, error) {
+	for i := range l {
+		if l[i].Symbol == symbol {
+			return &l[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w symbol: %s", ErrTokenUnknown, symbol)
+}
+
+// SetTokenByAddress sets tx.Token to the id of the token registered at addr.
+// ETH is addressed as the zero address.
+func (tx *Tx) SetTokenByAddress(addr string, resolver TokenResolver) error {
+	token, err := resolver.TokenByAddress(addr)
+	if err != nil {
+		return err
+	}
+	tx.Token = token.Id
+	return nil
+}
+
+// CheckTokenDecimals reports an error if decimals does not match the decimals
+// registered for the token with the given id.
+func CheckTokenDecimals(id uint64, decimals int, resolver TokenResolver) error {
+	token, err := resolver.TokenById(id)
+	if err != nil {
+		return err
+	}
+	if token.Decimals != decimals {
+		return fmt.Errorf("Token %s has %d decimals, got %d", token.Symbol, token.Decimals, decimals)
+	}
+	return nil
+}
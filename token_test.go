@@ -0,0 +1,69 @@
+package zinc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetTokenByAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		id   uint64
+	}{
+		{ETH.Address, 0},
+		{"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", 2},
+		// Addresses match regardless of case.
+		{"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", 2},
+	}
+	for _, tt := range tests {
+		tx := testTransfer()
+		tx.Token = 99
+		if err := tx.SetTokenByAddress(tt.addr, testTokens); err != nil {
+			t.Errorf("%s: %v", tt.addr, err)
+			continue
+		}
+		if tx.Token != tt.id {
+			t.Errorf("%s: token %d, want %d", tt.addr, tx.Token, tt.id)
+		}
+	}
+
+	tx := testTransfer()
+	if err := tx.SetTokenByAddress("0x6b175474e89094c44da98b954eedeac495271d0f", testTokens); err == nil {
+		t.Error("unknown address accepted")
+	}
+	if tx.Token != testTransfer().Token {
+		t.Errorf("unknown address changed the token to %d", tx.Token)
+	}
+}
+
+func TestCheckTokenDecimals(t *testing.T) {
+	tests := []struct {
+		id       uint64
+		decimals int
+		ok       bool
+	}{
+		{0, 18, true},
+		{2, 6, true},
+		{2, 18, false},
+		{0, 6, false},
+		{7, 18, false},
+	}
+	for _, tt := range tests {
+		err := CheckTokenDecimals(tt.id, tt.decimals, testTokens)
+		if (err == nil) != tt.ok {
+			t.Errorf("token %d, %d decimals: got %v, want ok %v", tt.id, tt.decimals, err, tt.ok)
+		}
+	}
+}
+
+func TestTokenListUnknown(t *testing.T) {
+	if _, err := testTokens.TokenById(7); !errors.Is(err, ErrTokenUnknown) {
+		t.Errorf("TokenById: got %v, want ErrTokenUnknown", err)
+	}
+	if _, err := testTokens.TokenByAddress(testTo); !errors.Is(err, ErrTokenUnknown) {
+		t.Errorf("TokenByAddress: got %v, want ErrTokenUnknown", err)
+	}
+	if _, err := testTokens.TokenBySymbol("DAI"); !errors.Is(err, ErrTokenUnknown) {
+		t.Errorf("TokenBySymbol: got %v, want ErrTokenUnknown", err)
+	}
+}
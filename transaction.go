@@ -0,0 +1,93 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	pubKeyLen        = 32
+	l2SignatureLen   = 64
+	ethSignatureType = "EthereumSignature"
+)
+
+// AssembleTransaction builds a Transaction from signatures produced out of
+// band, checking their lengths instead of re-signing. l1 may be nil for tx
+// types that do not require an Ethereum signature.
+func AssembleTransaction(tx *Tx, l2 *Signature, l1 *EthereumSignature) (*Transaction, error) {
+	if l2 == nil {
+		return nil, fmt.Errorf("Missing L2 signature")
+	}
+	if err := checkHexLen("pubKey", l2.PubKey, pubKeyLen); err != nil {
+		return nil, err
+	}
+	if err := checkHexLen("signature", l2.Signature, l2SignatureLen); err != nil {
+		return nil, err
+	}
+	assembled := &Transaction{Tx: *tx}
+	assembled.Tx.Signature = *l2
+	if l1 == nil {
+		if tx.requiresEthSignature() {
+			return nil, fmt.Errorf("%s requires an Ethereum signature", tx.Type)
+		}
+		return assembled, nil
+	}
+	if _, _, _, err := ParseEthSignature(l1.Signature); err != nil {
+		return nil, err
+	}
+	assembled.EthSignature = *l1
+	if assembled.EthSignature.Type == "" {
+		assembled.EthSignature.Type = ethSignatureType
+	}
+	return assembled, nil
+}
+
+func checkHexLen(name, s string, n int) error {
+	bytes, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("Invalid %s: %s", name, err)
+	}
+	if len(bytes) != n {
+		return fmt.Errorf("%s must be %d bytes long. len: %d", name, n, len(bytes))
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the signatures of a Transfer agree on who is
+// sending it: the L2 signature must be well formed and the Ethereum signature
+// must recover to tx.From, the owner of the account. symbol and decimals are
+// those of the transferred token, needed to rebuild the signed message.
+//
+// Whether the L2 public key is the one registered for the account cannot be
+// told offline; compare its hash with AccountState.Committed.PubKeyHash for
+// that.
+func (t *Transaction) VerifyConsistency(symbol string, decimals int) error {
+	if err := checkHexLen("pubKey", t.Tx.Signature.PubKey, pubKeyLen); err != nil {
+		return err
+	}
+	if err := checkHexLen("signature", t.Tx.Signature.Signature, l2SignatureLen); err != nil {
+		return err
+	}
+	if t.EthSignature.Signature == "" {
+		if t.Tx.requiresEthSignature() {
+			return fmt.Errorf("%s requires an Ethereum signature", t.Tx.Type)
+		}
+		return nil
+	}
+	from, err := serializeAddress(t.Tx.From)
+	if err != nil {
+		return err
+	}
+	signer, err := recoverEthSigner(t, symbol, decimals)
+	if err != nil {
+		return err
+	}
+	if signer == nil {
+		return fmt.Errorf("Ethereum signature does not recover to any address")
+	}
+	if !bytes.Equal(signer, from) {
+		return fmt.Errorf("Ethereum signature is by 0x%x, not the sender 0x%x", signer, from)
+	}
+	return nil
+}
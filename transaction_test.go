@@ -0,0 +1,96 @@
+package zinc
+
+import (
+	"strings"
+	"testing"
+)
+
+func testL2Signature() *Signature {
+	return &Signature{
+		PubKey:    strings.Repeat("ab", pubKeyLen),
+		Signature: strings.Repeat("cd", l2SignatureLen),
+	}
+}
+
+func TestAssembleTransactionChangePubKeyAuth(t *testing.T) {
+	tests := []struct {
+		auth    *ChangePubKeyAuthData
+		wantErr bool
+	}{
+		{nil, true},
+		{&ChangePubKeyAuthData{Type: ChangePubKeyECDSA}, true},
+		{&ChangePubKeyAuthData{Type: ChangePubKeyOnchain}, false},
+		{&ChangePubKeyAuthData{Type: ChangePubKeyCREATE2}, false},
+	}
+	for _, tt := range tests {
+		name := "no auth data"
+		if tt.auth != nil {
+			name = string(tt.auth.Type)
+		}
+		t.Run(name, func(t *testing.T) {
+			tx := testChangePubKey()
+			tx.EthAuthData = tt.auth
+			assembled, err := AssembleTransaction(tx, testL2Signature(), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AssembleTransaction without an L1 signature: err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := assembled.VerifyConsistency("ETH", 18); err != nil {
+				t.Errorf("VerifyConsistency: %v", err)
+			}
+		})
+	}
+}
+
+func TestAssembleTransactionChecksSignatureLengths(t *testing.T) {
+	sig := testL2Signature()
+	sig.Signature = sig.Signature[2:]
+	if _, err := AssembleTransaction(testTransfer(), sig, nil); err == nil {
+		t.Fatal("63-byte L2 signature accepted")
+	}
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	signed := func(index uint32) *Transaction {
+		txn := testSignedTransfer(t, index)
+		txn.Tx.Signature = *testL2Signature()
+		return txn
+	}
+	if err := signed(0).VerifyConsistency("ETH", 18); err != nil {
+		t.Errorf("consistent pair: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		txn  *Transaction
+	}{
+		{"eth signature by another key", signed(1)},
+		{"missing eth signature", func() *Transaction {
+			txn := signed(0)
+			txn.EthSignature = EthereumSignature{}
+			return txn
+		}()},
+		{"short L2 signature", func() *Transaction {
+			txn := signed(0)
+			txn.Tx.Signature.Signature = txn.Tx.Signature.Signature[2:]
+			return txn
+		}()},
+		{"missing L2 public key", func() *Transaction {
+			txn := signed(0)
+			txn.Tx.Signature.PubKey = ""
+			return txn
+		}()},
+		{"malformed eth signature", func() *Transaction {
+			txn := signed(0)
+			txn.EthSignature.Signature = "0x1234"
+			return txn
+		}()},
+	}
+	for _, tt := range tests {
+		if err := tt.txn.VerifyConsistency("ETH", 18); err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+}
@@ -0,0 +1,760 @@
+package zinc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	MAX_NUMBER_OF_ACCOUNTS = 16777216 // math.Pow(2, 24)
+	MAX_NUMBER_OF_TOKENS   = 128
+	// NFTs use token ids from MIN_NFT_TOKEN_ID up to MAX_NFT_TOKEN_ID.
+	MIN_NFT_TOKEN_ID = 65536          // math.Pow(2, 16)
+	MAX_NFT_TOKEN_ID = math.MaxUint32 // math.Pow(2, 32) - 1
+)
+
+// TxType is the transaction type name used in the "type" field of the input JSON.
+type TxType string
+
+const (
+	TxTypeWithdraw     TxType = "Withdraw"
+	TxTypeTransfer     TxType = "Transfer"
+	TxTypeChangePubKey TxType = "ChangePubKey"
+	TxTypeForcedExit   TxType = "ForcedExit"
+)
+
+var txTypes = []TxType{TxTypeWithdraw, TxTypeTransfer, TxTypeChangePubKey, TxTypeForcedExit}
+
+// TxTypeCase selects how TxType values are spelled by MarshalJSONTypeCase.
+type TxTypeCase int
+
+const (
+	// TxTypeCaseDefault writes type names as defined, e.g. "Transfer".
+	TxTypeCaseDefault TxTypeCase = iota
+	// TxTypeCaseLower writes type names in lowercase, e.g. "transfer".
+	TxTypeCaseLower
+)
+
+// UnmarshalJSON matches known type names case-insensitively and stores them
+// in their defined spelling.
+func (t *TxType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*t = TxType(name)
+	for _, known := range txTypes {
+		if strings.EqualFold(name, string(known)) {
+			*t = known
+			break
+		}
+	}
+	return nil
+}
+
+// Type discriminators prepended to serialized transactions.
+const (
+	withdrawTypeByte     byte = 0x03
+	transferTypeByte     byte = 0x05
+	changePubKeyTypeByte byte = 0x07
+	forcedExitTypeByte   byte = 0x08
+)
+
+// TypeByte returns the type discriminator that prefixes the serialization of t.
+func TypeByte(t TxType) (byte, error) {
+	switch t {
+	case TxTypeWithdraw:
+		return withdrawTypeByte, nil
+	case TxTypeTransfer:
+		return transferTypeByte, nil
+	case TxTypeChangePubKey:
+		return changePubKeyTypeByte, nil
+	case TxTypeForcedExit:
+		return forcedExitTypeByte, nil
+	}
+	return 0, fmt.Errorf("Unknown transaction type: %s", t)
+}
+
+// txTypeOf returns the type of a serialized transaction from its ProtocolV1
+// type byte or its ProtocolV2 marker and version byte.
+func txTypeOf(data []byte) (TxType, bool) {
+	if len(data) == 0 {
+		return "", false
+	}
+	for _, t := range txTypes {
+		b, _ := TypeByte(t)
+		if data[0] == b || (data[0] == 0xff-b && len(data) > 1 && data[1] == txVersionByte) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// VerifyTypeByte reports an error unless data starts with the type marker of
+// tx.Type, in either protocol layout.
+func VerifyTypeByte(data []byte, tx *Tx) error {
+	if _, err := TypeByte(tx.Type); err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("Serialized transaction is empty")
+	}
+	got, ok := txTypeOf(data)
+	if !ok {
+		return fmt.Errorf("Unknown type byte 0x%02x, expected %s", data[0], tx.Type)
+	}
+	if got != tx.Type {
+		return fmt.Errorf("Type byte 0x%02x is a %s, expected %s", data[0], got, tx.Type)
+	}
+	return nil
+}
+
+// ChangePubKeyAuthType is the way a ChangePubKey proves control of the L1 account.
+type ChangePubKeyAuthType string
+
+const (
+	ChangePubKeyOnchain ChangePubKeyAuthType = "Onchain"
+	ChangePubKeyECDSA   ChangePubKeyAuthType = "ECDSA"
+	ChangePubKeyCREATE2 ChangePubKeyAuthType = "CREATE2"
+)
+
+// RequiresEthSignature reports whether a transaction of type txType must carry
+// an ethereumSignature. ForcedExit never does. ChangePubKey only does with
+// ECDSA auth, which is assumed when authType is omitted; Onchain and CREATE2
+// auth are proven on L1 instead.
+func RequiresEthSignature(txType TxType, authType ...ChangePubKeyAuthType) bool {
+	switch txType {
+	case TxTypeForcedExit:
+		return false
+	case TxTypeChangePubKey:
+		return len(authType) == 0 || authType[0] == ChangePubKeyECDSA
+	}
+	return true
+}
+
+// requiresEthSignature is RequiresEthSignature for tx, taking the auth type of
+// a ChangePubKey from its EthAuthData.
+func (tx *Tx) requiresEthSignature() bool {
+	if tx.EthAuthData != nil {
+		return RequiresEthSignature(tx.Type, tx.EthAuthData.Type)
+	}
+	return RequiresEthSignature(tx.Type)
+}
+
+type ContractInput struct {
+	Arguments   interface{} `json:"arguments"`
+	Transaction Transaction `json:"transaction"`
+}
+
+type Transaction struct {
+	Tx           Tx                `json:"tx"`
+	EthSignature EthereumSignature `json:"ethereumSignature"`
+}
+
+type Tx struct {
+	Type      TxType    `json:"type"`
+	AccountId uint64    `json:"accountId"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Token     uint64    `json:"token"`
+	Amount    BigInt    `json:"amount"`
+	Fee       BigInt    `json:"fee"`
+	Nonce     uint64    `json:"nonce"`
+	Signature Signature `json:"signature"`
+	// ValidFrom and ValidUntil bound when the tx may execute, in UNIX seconds.
+	ValidFrom  uint64 `json:"validFrom"`
+	ValidUntil uint64 `json:"validUntil"`
+	Memo       []byte `json:"memo,omitempty"`
+
+	// ChangePubKey fields.
+	Account     string                `json:"account,omitempty"`
+	NewPkHash   string                `json:"newPkHash,omitempty"`
+	FeeToken    uint64                `json:"feeToken,omitempty"`
+	EthAuthData *ChangePubKeyAuthData `json:"ethAuthData,omitempty"`
+
+	// ForcedExit fields.
+	InitiatorAccountId uint64 `json:"initiatorAccountId,omitempty"`
+	Target             string `json:"target,omitempty"`
+}
+
+// ChangePubKeyAuthData proves that the owner of the L1 account authorized a
+// ChangePubKey. Which fields are set depends on Type.
+type ChangePubKeyAuthData struct {
+	Type ChangePubKeyAuthType `json:"type"`
+	// ECDSA
+	EthSignature string `json:"ethSignature,omitempty"`
+	BatchHash    string `json:"batchHash,omitempty"`
+	// CREATE2
+	CreatorAddress string `json:"creatorAddress,omitempty"`
+	SaltArg        string `json:"saltArg,omitempty"`
+	CodeHash       string `json:"codeHash,omitempty"`
+}
+
+type Signature struct {
+	PubKey    string `json:"pubKey"`
+	Signature string `json:"signature"`
+}
+
+type EthereumSignature struct {
+	Type      string `json:"type"`
+	Signature string `json:"signature"`
+}
+
+// MarshalJSONTypeCase encodes tx like json.Marshal, with the type name spelled
+// in typeCase, for consumers that expect e.g. "transfer". json.Marshal itself
+// always writes the name as defined, which is what the server and zksync.js
+// expect.
+func (tx *Tx) MarshalJSONTypeCase(typeCase TxTypeCase) ([]byte, error) {
+	c := *tx
+	if typeCase == TxTypeCaseLower {
+		c.Type = TxType(strings.ToLower(string(tx.Type)))
+	}
+	return json.Marshal(&c)
+}
+
+// Uint2bytes converts uint64 to []byte
+// https://qiita.com/ryskiwt/items/17617d4f3e8dde7c2b8e
+func Uint2bytes(i uint64, size int) []byte {
+	bytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(bytes, i)
+	return bytes[8-size : 8]
+}
+
+// appendUint appends the low size bytes of i to dst, big-endian.
+func appendUint(dst []byte, i uint64, size int) []byte {
+	for shift := 8 * (size - 1); shift >= 0; shift -= 8 {
+		dst = append(dst, byte(i>>uint(shift)))
+	}
+	return dst
+}
+
+func appendAccountId(dst []byte, id uint64) ([]byte, error) {
+	if id >= MAX_NUMBER_OF_ACCOUNTS {
+		return nil, ErrAccountIdTooBig
+	}
+	return appendUint(dst, id, 4), nil
+}
+
+// removeAddressPrefix strips "0x", "sync:" or one of the extra prefixes given
+// with WithAddressPrefix.
+func removeAddressPrefix(address string, extra []string) (string, error) {
+	if strings.HasPrefix(address, "0x") {
+		return address[2:], nil
+	}
+	if strings.HasPrefix(address, "sync:") {
+		return address[5:], nil
+	}
+	for _, prefix := range extra {
+		if strings.HasPrefix(address, prefix) {
+			return address[len(prefix):], nil
+		}
+	}
+	if len(extra) > 0 {
+		return "", fmt.Errorf("%w or one of %q", ErrBadAddressPrefix, extra)
+	}
+	return "", ErrBadAddressPrefix
+}
+
+// arrayifyAddress decodes the hex digits of an address without its prefix.
+// An odd number of digits, as left by a truncated address, is an error.
+func arrayifyAddress(address string) ([]byte, error) {
+	bytes, err := hex.DecodeString(address)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid hex address: %w", err)
+	}
+	return bytes, nil
+}
+
+func serializeAddress(address string) ([]byte, error) {
+	prefixless, err := removeAddressPrefix(address, nil)
+	if err != nil {
+		return nil, err
+	}
+	return appendHexAddress(nil, prefixless)
+}
+
+// appendHexAddress appends the 20 bytes of a prefixless hex address to dst.
+// Well-formed addresses are decoded in place.
+func appendHexAddress(dst []byte, prefixless string) ([]byte, error) {
+	if len(prefixless) == 2*20 {
+		if res, ok := appendHex(dst, prefixless); ok {
+			return res, nil
+		}
+	}
+	bytes, err := arrayifyAddress(prefixless)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes) != 20 {
+		return nil, &AddressLengthError{Length: len(bytes)}
+	}
+	return append(dst, bytes...), nil
+}
+
+// appendHex appends the bytes of the hex string s to dst. ok is false if s
+// has an odd length or a non-hex digit.
+func appendHex(dst []byte, s string) (res []byte, ok bool) {
+	if len(s)%2 != 0 {
+		return nil, false
+	}
+	for i := 0; i < len(s); i += 2 {
+		hi, ok := fromHexChar(s[i])
+		if !ok {
+			return nil, false
+		}
+		lo, ok := fromHexChar(s[i+1])
+		if !ok {
+			return nil, false
+		}
+		dst = append(dst, hi<<4|lo)
+	}
+	return dst, true
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+func appendPubKeyHash(dst []byte, pubKeyHash string) ([]byte, error) {
+	if !strings.HasPrefix(pubKeyHash, "sync:") {
+		return nil, fmt.Errorf("PubKeyHash must start with 'sync:'")
+	}
+	bytes, err := arrayifyAddress(strings.TrimPrefix(pubKeyHash, "sync:"))
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes) != 20 {
+		return nil, fmt.Errorf("PubKeyHash must be 20 bytes long. len: %d", len(bytes))
+	}
+	return append(dst, bytes...), nil
+}
+
+// appendTokenId appends the token of a transaction that may move either a
+// fungible token or an NFT.
+func appendTokenId(dst []byte, tokenId uint64, version ProtocolVersion) ([]byte, error) {
+	if tokenId >= MIN_NFT_TOKEN_ID {
+		return appendNFTTokenId(dst, tokenId, version)
+	}
+	return appendFungibleTokenId(dst, tokenId, version)
+}
+
+// appendFungibleTokenId appends a token id that must not be an NFT, such as a
+// fee token.
+func appendFungibleTokenId(dst []byte, tokenId uint64, version ProtocolVersion) ([]byte, error) {
+	if tokenId >= MIN_NFT_TOKEN_ID {
+		return nil, fmt.Errorf("TokenId %d is an NFT, expected a fungible token", tokenId)
+	}
+	if tokenId >= MAX_NUMBER_OF_TOKENS {
+		return nil, ErrTokenIdTooBig
+	}
+	return appendUint(dst, tokenId, tokenIdSize(version)), nil
+}
+
+// appendNFTTokenId appends a token id that must be an NFT. NFT ids do not fit
+// the 2-byte ProtocolV1 encoding.
+func appendNFTTokenId(dst []byte, tokenId uint64, version ProtocolVersion) ([]byte, error) {
+	if tokenId < MIN_NFT_TOKEN_ID {
+		return nil, fmt.Errorf("TokenId %d is not an NFT: NFT ids start at %d", tokenId, MIN_NFT_TOKEN_ID)
+	}
+	if tokenId > MAX_NFT_TOKEN_ID {
+		return nil, ErrTokenIdTooBig
+	}
+	if version < ProtocolV2 {
+		return nil, fmt.Errorf("NFT TokenId %d needs ProtocolV2", tokenId)
+	}
+	return appendUint(dst, tokenId, tokenIdSize(version)), nil
+}
+
+func tokenIdSize(version ProtocolVersion) int {
+	if version >= ProtocolV2 {
+		return 4
+	}
+	return 2
+}
+
+// Packed amounts are a 35-bit mantissa and a 5-bit exponent; packed fees are
+// an 11-bit mantissa and a 5-bit exponent.
+const (
+	packedAmountSize = 5
+	packedFeeSize    = 2
+)
+
+// PackedAmountSize returns the length in bytes of a packed transaction amount.
+func PackedAmountSize() int {
+	return packedAmountSize
+}
+
+// PackedFeeSize returns the length in bytes of a packed transaction fee.
+func PackedFeeSize() int {
+	return packedFeeSize
+}
+
+func appendAmountPacked(dst []byte, amount BigInt) ([]byte, error) {
+	return appendPacked(dst, amount.Value(), amountExpBits, amountMantissaBits)
+}
+
+func appendFeePacked(dst []byte, fee BigInt) ([]byte, error) {
+	return appendPacked(dst, fee.Value(), feeExpBits, feeMantissaBits)
+}
+
+// fullAmountSize is the length of an unpacked amount, a big-endian uint128.
+const fullAmountSize = 16
+
+func appendAmountFull(dst []byte, amount BigInt) ([]byte, error) {
+	value := amount.Value()
+	if value.Sign() < 0 || value.BitLen() > fullAmountSize*8 {
+		return nil, fmt.Errorf("Amount does not fit in %d bytes: %s", fullAmountSize, amount)
+	}
+	n := len(dst)
+	for i := 0; i < fullAmountSize; i++ {
+		dst = append(dst, 0)
+	}
+	value.FillBytes(dst[n:])
+	return dst, nil
+}
+
+func appendNonce(dst []byte, nonce uint64) []byte {
+	return appendUint(dst, nonce, 4)
+}
+
+// DefaultValidUntil leaves a transaction valid indefinitely.
+const DefaultValidUntil = math.MaxUint64
+
+// suggestedValidity is how long a transaction from SuggestedValidWindow stays
+// valid.
+const suggestedValidity = time.Hour
+
+// SuggestedValidWindow returns a validity window the server accepts for a
+// transaction created at now: valid immediately and for the next hour.
+func SuggestedValidWindow(now time.Time) (from, until uint64) {
+	return 0, uint64(now.Add(suggestedValidity).Unix())
+}
+
+// appendTimestamp encodes UNIX seconds as 8 bytes big-endian. Timestamps are
+// unsigned, so negative values are already rejected when the JSON input is
+// decoded.
+func appendTimestamp(dst []byte, ts uint64) []byte {
+	return appendUint(dst, ts, 8)
+}
+
+// MaxMemoLength is the longest memo that can be attached to a transfer.
+const MaxMemoLength = 64
+
+// SerializeOption configures optional parts of a transaction serialization.
+type SerializeOption func(*serializeConfig)
+
+type serializeConfig struct {
+	version  ProtocolVersion
+	memo     bool
+	checksum bool
+	prefixes []string
+}
+
+func newSerializeConfig(opts []SerializeOption) (serializeConfig, error) {
+	cfg := serializeConfig{version: DefaultProtocolVersion}
+	if len(opts) > 0 {
+		// Options take a pointer, which moves the config to the heap. Keep
+		// that off the common path without options.
+		c := cfg
+		for _, opt := range opts {
+			opt(&c)
+		}
+		cfg = c
+	}
+	if cfg.version != ProtocolV1 && cfg.version != ProtocolV2 {
+		return cfg, fmt.Errorf("Unsupported protocol version: %d", cfg.version)
+	}
+	return cfg, nil
+}
+
+// ProtocolVersion selects the transaction byte layout of a zkSync deployment.
+type ProtocolVersion int
+
+const (
+	// ProtocolV1 starts each transaction with its type byte and encodes
+	// token ids in 2 bytes.
+	ProtocolV1 ProtocolVersion = 1
+	// ProtocolV2 starts each transaction with 0xff minus its type byte
+	// followed by a version byte, and encodes token ids in 4 bytes.
+	ProtocolV2 ProtocolVersion = 2
+)
+
+// DefaultProtocolVersion is the layout used when no WithProtocolVersion
+// option is given.
+const DefaultProtocolVersion = ProtocolV1
+
+// txVersionByte follows the type marker in the ProtocolV2 layout.
+const txVersionByte byte = 0x01
+
+// WithProtocolVersion serializes for the given protocol version.
+func WithProtocolVersion(version ProtocolVersion) SerializeOption {
+	return func(c *serializeConfig) {
+		c.version = version
+	}
+}
+
+// WithAddressPrefix accepts addresses that start with prefix in addition to
+// "0x" and "sync:", for forks with their own L2 address prefix such as "zk:".
+// It may be given several times.
+func WithAddressPrefix(prefix string) SerializeOption {
+	return func(c *serializeConfig) {
+		c.prefixes = append(c.prefixes, prefix)
+	}
+}
+
+func appendTypePrefix(dst []byte, typeByte byte, version ProtocolVersion) []byte {
+	if version >= ProtocolV2 {
+		return append(dst, 0xff-typeByte, txVersionByte)
+	}
+	return append(dst, typeByte)
+}
+
+// WithMemo appends tx.Memo to the serialization as a one-byte length followed
+// by the memo bytes. This is an extension for forks that support memos; the
+// zkSync protocol itself has no memo field.
+func WithMemo() SerializeOption {
+	return func(c *serializeConfig) {
+		c.memo = true
+	}
+}
+
+func appendMemo(dst []byte, memo []byte) ([]byte, error) {
+	if len(memo) > MaxMemoLength {
+		return nil, fmt.Errorf("Memo is too long: at most %d bytes, got %d", MaxMemoLength, len(memo))
+	}
+	dst = append(dst, byte(len(memo)))
+	return append(dst, memo...), nil
+}
+
+// SerializeTransfer serializes a Transfer for signing. The recipient is always
+// encoded by address, so a transfer to an address that has no account yet is
+// serialized the same way; the operator turns it into a TransferToNew op.
+func SerializeTransfer(tx *Tx, opts ...SerializeOption) ([]byte, error) {
+	return AppendSerializeTransfer(nil, tx, opts...)
+}
+
+// AppendSerializeTransfer appends the serialization of tx to dst and returns
+// the extended buffer, like strconv.AppendInt. The fields are encoded straight
+// into dst, so a reused buffer with enough capacity makes a call without
+// options allocation-free, as long as the amount and fee fit in a uint64.
+func AppendSerializeTransfer(dst []byte, tx *Tx, opts ...SerializeOption) ([]byte, error) {
+	return appendSerialized(dst, TxTypeTransfer, tx, opts)
+}
+
+func transferFields(dst []byte, tx *Tx, cfg serializeConfig) ([]byte, error) {
+	dst = appendTypePrefix(dst, transferTypeByte, cfg.version)
+	dst, err := appendAccountId(dst, tx.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if dst, err = cfg.appendAddress(dst, tx.From); err != nil {
+		return nil, err
+	}
+	if dst, err = cfg.appendAddress(dst, tx.To); err != nil {
+		return nil, err
+	}
+	if dst, err = appendTokenId(dst, tx.Token, cfg.version); err != nil {
+		return nil, err
+	}
+	if dst, err = appendAmountPacked(dst, tx.Amount); err != nil {
+		return nil, err
+	}
+	if dst, err = appendFeePacked(dst, tx.Fee); err != nil {
+		return nil, err
+	}
+	dst = appendNonce(dst, tx.Nonce)
+	dst = appendTimestamp(dst, tx.ValidFrom)
+	dst = appendTimestamp(dst, tx.ValidUntil)
+	if cfg.memo {
+		return appendMemo(dst, tx.Memo)
+	}
+	return dst, nil
+}
+
+// SerializeWithdraw serializes a Withdraw for signing. Unlike a transfer, the
+// withdrawn amount is not packed but encoded in full as 16 bytes.
+func SerializeWithdraw(tx *Tx, opts ...SerializeOption) ([]byte, error) {
+	return appendSerialized(nil, TxTypeWithdraw, tx, opts)
+}
+
+func withdrawFields(dst []byte, tx *Tx, cfg serializeConfig) ([]byte, error) {
+	dst = appendTypePrefix(dst, withdrawTypeByte, cfg.version)
+	dst, err := appendAccountId(dst, tx.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if dst, err = cfg.appendAddress(dst, tx.From); err != nil {
+		return nil, err
+	}
+	if dst, err = cfg.appendAddress(dst, tx.To); err != nil {
+		return nil, err
+	}
+	if dst, err = appendFungibleTokenId(dst, tx.Token, cfg.version); err != nil {
+		return nil, err
+	}
+	if dst, err = appendAmountFull(dst, tx.Amount); err != nil {
+		return nil, err
+	}
+	if dst, err = appendFeePacked(dst, tx.Fee); err != nil {
+		return nil, err
+	}
+	dst = appendNonce(dst, tx.Nonce)
+	dst = appendTimestamp(dst, tx.ValidFrom)
+	return appendTimestamp(dst, tx.ValidUntil), nil
+}
+
+// SerializeChangePubKey serializes the core ChangePubKey message for signing.
+// The auth data is not part of the signed bytes.
+func SerializeChangePubKey(tx *Tx, opts ...SerializeOption) ([]byte, error) {
+	return appendSerialized(nil, TxTypeChangePubKey, tx, opts)
+}
+
+func changePubKeyFields(dst []byte, tx *Tx, cfg serializeConfig) ([]byte, error) {
+	dst = appendTypePrefix(dst, changePubKeyTypeByte, cfg.version)
+	dst, err := appendAccountId(dst, tx.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if dst, err = cfg.appendAddress(dst, tx.Account); err != nil {
+		return nil, err
+	}
+	if dst, err = appendPubKeyHash(dst, tx.NewPkHash); err != nil {
+		return nil, err
+	}
+	if dst, err = appendFungibleTokenId(dst, tx.FeeToken, cfg.version); err != nil {
+		return nil, err
+	}
+	if dst, err = appendFeePacked(dst, tx.Fee); err != nil {
+		return nil, err
+	}
+	dst = appendNonce(dst, tx.Nonce)
+	dst = appendTimestamp(dst, tx.ValidFrom)
+	return appendTimestamp(dst, tx.ValidUntil), nil
+}
+
+// SerializeForcedExit serializes a ForcedExit for signing. There is no amount
+// field since the target's whole balance is withdrawn, so tx.Amount is ignored.
+func SerializeForcedExit(tx *Tx, opts ...SerializeOption) ([]byte, error) {
+	return appendSerialized(nil, TxTypeForcedExit, tx, opts)
+}
+
+func forcedExitFields(dst []byte, tx *Tx, cfg serializeConfig) ([]byte, error) {
+	dst = appendTypePrefix(dst, forcedExitTypeByte, cfg.version)
+	dst, err := appendAccountId(dst, tx.InitiatorAccountId)
+	if err != nil {
+		return nil, err
+	}
+	if dst, err = cfg.appendAddress(dst, tx.Target); err != nil {
+		return nil, err
+	}
+	if dst, err = appendFungibleTokenId(dst, tx.Token, cfg.version); err != nil {
+		return nil, err
+	}
+	if dst, err = appendFeePacked(dst, tx.Fee); err != nil {
+		return nil, err
+	}
+	dst = appendNonce(dst, tx.Nonce)
+	dst = appendTimestamp(dst, tx.ValidFrom)
+	return appendTimestamp(dst, tx.ValidUntil), nil
+}
+
+// fieldsFunc appends the fields of one transaction type to dst in
+// serialization order, starting with the type prefix.
+type fieldsFunc func(dst []byte, tx *Tx, cfg serializeConfig) ([]byte, error)
+
+func txFieldsFunc(txType TxType) (fieldsFunc, error) {
+	switch txType {
+	case TxTypeTransfer:
+		return transferFields, nil
+	case TxTypeWithdraw:
+		return withdrawFields, nil
+	case TxTypeChangePubKey:
+		return changePubKeyFields, nil
+	case TxTypeForcedExit:
+		return forcedExitFields, nil
+	}
+	return nil, fmt.Errorf("Unknown transaction type: %q", txType)
+}
+
+// serializedSize returns the length of tx serialized in the layout of
+// txType, so the output can be sized before any field is encoded.
+func serializedSize(txType TxType, tx *Tx, cfg serializeConfig) (int, error) {
+	n, err := txLength(txType)
+	if err != nil {
+		return 0, err
+	}
+	if cfg.version >= ProtocolV2 {
+		// The version byte, and 4 instead of 2 bytes of token id.
+		n += 1 + 2
+	}
+	if cfg.memo && txType == TxTypeTransfer {
+		n += 1 + len(tx.Memo)
+	}
+	return n, nil
+}
+
+// appendSerialized appends tx serialized in the layout of txType to dst. Each
+// layout has a fixed size, so dst is grown at most once.
+func appendSerialized(dst []byte, txType TxType, tx *Tx, opts []SerializeOption) ([]byte, error) {
+	f, err := txFieldsFunc(txType)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := newSerializeConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	size, err := serializedSize(txType, tx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cap(dst)-len(dst) < size {
+		grown := make([]byte, len(dst), len(dst)+size)
+		copy(grown, dst)
+		dst = grown
+	}
+	return f(dst, tx, cfg)
+}
+
+// SerializeTx serializes tx for signing with the serializer matching tx.Type.
+// The type must be spelled exactly as in the input JSON, e.g. "Transfer".
+func SerializeTx(tx *Tx, opts ...SerializeOption) ([]byte, error) {
+	return appendSerialized(nil, tx.Type, tx, opts)
+}
+
+// SerializeTxTo writes the serialization of tx to w and returns the number of
+// bytes written. A writer that accepts fewer bytes than given without an
+// error fails with io.ErrShortWrite.
+//
+// The serialization is built first and written with a single Write rather
+// than streamed field by field: the append serializers encode into one
+// exactly sized buffer, which costs less than a Write per field, and w never
+// sees a partial transaction when a later field fails to encode.
+func SerializeTxTo(w io.Writer, tx *Tx, opts ...SerializeOption) (int, error) {
+	ser, err := SerializeTx(tx, opts...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(ser)
+	if err != nil {
+		return n, err
+	}
+	if n < len(ser) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
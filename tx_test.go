@@ -0,0 +1,665 @@
+package zinc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testFrom = "0x36615cf349d7f6344891b1e7ca7c72883f5dc049"
+	testTo   = "0x1234567812345678123456781234567812345678"
+)
+
+// testTransfer returns a transfer of 1.5 ETH-sized units with the fee from
+// data/input.json.
+func testTransfer() *Tx {
+	return &Tx{
+		Type:       TxTypeTransfer,
+		AccountId:  7,
+		From:       testFrom,
+		To:         testTo,
+		Token:      3,
+		Amount:     NewBigInt(big.NewInt(1500000000000000000)),
+		Fee:        NewBigInt(big.NewInt(37500000000000)),
+		Nonce:      12,
+		ValidFrom:  0,
+		ValidUntil: 4294967295,
+	}
+}
+
+func TestSerializeAddressLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		length  int
+		want    string
+	}{
+		{"19 bytes", "0x" + strings.Repeat("ab", 19), 19, "too short"},
+		{"21 bytes", "0x" + strings.Repeat("ab", 21), 21, "too long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := serializeAddress(tt.address)
+			var lenErr *AddressLengthError
+			if !errors.As(err, &lenErr) {
+				t.Fatalf("got %v, want an *AddressLengthError", err)
+			}
+			if lenErr.Length != tt.length {
+				t.Errorf("Length = %d, want %d", lenErr.Length, tt.length)
+			}
+			if !errors.Is(err, ErrAddressLength) {
+				t.Errorf("%v does not unwrap to ErrAddressLength", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not say %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerializeAddressOddLength(t *testing.T) {
+	truncated := "0x" + strings.Repeat("a", 39)
+	if _, err := serializeAddress(truncated); err == nil {
+		t.Fatal("39 hex digits serialized without an error")
+	}
+	tx := &Tx{Type: TxTypeTransfer, To: truncated}
+	if err := tx.Validate(); err == nil {
+		t.Fatal("Validate accepted a 39-digit recipient")
+	}
+}
+
+func TestSerializeAddressInvalidHex(t *testing.T) {
+	if _, err := serializeAddress("0x" + strings.Repeat("zz", 20)); err == nil {
+		t.Fatal("non-hex address serialized without an error")
+	}
+}
+
+func TestAppendSerializeTransfer(t *testing.T) {
+	tx := testTransfer()
+	want, err := SerializeTransfer(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := []byte{0xaa, 0xbb}
+	got, err := AppendSerializeTransfer(append([]byte(nil), prefix...), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, append(prefix, want...)) {
+		t.Errorf("got %x, want %x followed by %x", got, prefix, want)
+	}
+}
+
+func TestAppendSerializeTransferAllocs(t *testing.T) {
+	tx := testTransfer()
+	buf := make([]byte, 0, 128)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := AppendSerializeTransfer(buf[:0], tx); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("AppendSerializeTransfer into a buffer with capacity made %v allocations, want 0", allocs)
+	}
+}
+
+func BenchmarkAppendSerializeTransfer(b *testing.B) {
+	tx := testTransfer()
+	buf := make([]byte, 0, 128)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = AppendSerializeTransfer(buf[:0], tx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalJSONTypeCase(t *testing.T) {
+	tx := testTransfer()
+	lower, err := tx.MarshalJSONTypeCase(TxTypeCaseLower)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(lower, []byte(`"type":"transfer"`)) {
+		t.Errorf("lowercase output %s has no \"type\":\"transfer\"", lower)
+	}
+	if tx.Type != TxTypeTransfer {
+		t.Errorf("MarshalJSONTypeCase changed tx.Type to %q", tx.Type)
+	}
+
+	// Everything else, including what is sent to the node, keeps the
+	// defined spelling.
+	def, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(def, []byte(`"type":"Transfer"`)) {
+		t.Errorf("json.Marshal output %s has no \"type\":\"Transfer\"", def)
+	}
+
+	var parsed Tx
+	if err := json.Unmarshal(lower, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Type != TxTypeTransfer {
+		t.Errorf("parsed type %q, want %q", parsed.Type, TxTypeTransfer)
+	}
+}
+
+// concatHex joins hex-encoded fields into one expected serialization.
+func concatHex(t *testing.T, fields ...string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.Join(fields, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSerializeWithdraw(t *testing.T) {
+	// Not packable, so a packed encoding could not hide a mistake.
+	amount, _ := new(big.Int).SetString("1234567890123456789012345", 10)
+	tx := testTransfer()
+	tx.Type = TxTypeWithdraw
+	tx.Amount = NewBigInt(amount)
+	got, err := SerializeWithdraw(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := concatHex(t,
+		"03",       // type
+		"00000007", // accountId
+		"36615cf349d7f6344891b1e7ca7c72883f5dc049", // from
+		"1234567812345678123456781234567812345678", // to
+		"0003",                             // token
+		"000000000001056e0f36a6443de2df79", // amount, 16 bytes unpacked
+		"2eeb",                             // packed fee
+		"0000000c",                         // nonce
+		"0000000000000000",                 // validFrom
+		"00000000ffffffff",                 // validUntil
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %x\nwant %x", got, want)
+	}
+	if len(got) != withdrawLength {
+		t.Errorf("length %d, want withdrawLength %d", len(got), withdrawLength)
+	}
+}
+
+func TestSerializeWithdrawAmountTooBig(t *testing.T) {
+	tx := testTransfer()
+	tx.Type = TxTypeWithdraw
+	tx.Amount = NewBigInt(new(big.Int).Lsh(big.NewInt(1), 128))
+	if _, err := SerializeWithdraw(tx); err == nil {
+		t.Fatal("2^128 serialized as a 16-byte amount")
+	}
+}
+
+// testPubKeyHash is the hash of the public key of the zero-seed private key.
+const testPubKeyHash = "sync:c7712716b9ef6bd21753c4e91decc351b111c06d"
+
+func testChangePubKey() *Tx {
+	return &Tx{
+		Type:       TxTypeChangePubKey,
+		AccountId:  7,
+		Account:    testFrom,
+		NewPkHash:  testPubKeyHash,
+		FeeToken:   2,
+		Fee:        NewBigInt(big.NewInt(37500000000000)),
+		Nonce:      12,
+		ValidUntil: 4294967295,
+	}
+}
+
+func TestSerializeChangePubKey(t *testing.T) {
+	tx := testChangePubKey()
+	tx.EthAuthData = &ChangePubKeyAuthData{Type: ChangePubKeyOnchain}
+	got, err := SerializeChangePubKey(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := concatHex(t,
+		"07",       // type
+		"00000007", // accountId
+		"36615cf349d7f6344891b1e7ca7c72883f5dc049", // account
+		"c7712716b9ef6bd21753c4e91decc351b111c06d", // newPkHash
+		"0002",             // feeToken
+		"2eeb",             // packed fee
+		"0000000c",         // nonce
+		"0000000000000000", // validFrom
+		"00000000ffffffff", // validUntil
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %x\nwant %x", got, want)
+	}
+	if len(got) != changePubKeyLength {
+		t.Errorf("length %d, want changePubKeyLength %d", len(got), changePubKeyLength)
+	}
+}
+
+func TestSerializeChangePubKeyBadPubKeyHash(t *testing.T) {
+	tests := map[string]string{
+		"0x prefix":    "0xc7712716b9ef6bd21753c4e91decc351b111c06d",
+		"19 bytes":     "sync:c7712716b9ef6bd21753c4e91decc351b111c0",
+		"odd length":   "sync:c7712716b9ef6bd21753c4e91decc351b111c06",
+		"not hex":      "sync:z7712716b9ef6bd21753c4e91decc351b111c06d",
+		"missing hash": "",
+	}
+	for name, hash := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := testChangePubKey()
+			tx.NewPkHash = hash
+			if _, err := SerializeChangePubKey(tx); err == nil {
+				t.Errorf("newPkHash %q serialized without an error", hash)
+			}
+		})
+	}
+}
+
+func testForcedExit() *Tx {
+	return &Tx{
+		Type:               TxTypeForcedExit,
+		InitiatorAccountId: 9,
+		Target:             testTo,
+		Token:              3,
+		Fee:                NewBigInt(big.NewInt(37500000000000)),
+		Nonce:              12,
+		ValidUntil:         4294967295,
+	}
+}
+
+func TestSerializeForcedExit(t *testing.T) {
+	tx := testForcedExit()
+	// ForcedExit has no amount field, so this must not show up.
+	tx.Amount = NewBigInt(big.NewInt(1000))
+	got, err := SerializeForcedExit(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := concatHex(t,
+		"08",       // type
+		"00000009", // initiatorAccountId
+		"1234567812345678123456781234567812345678", // target
+		"0003",             // token
+		"2eeb",             // packed fee
+		"0000000c",         // nonce
+		"0000000000000000", // validFrom
+		"00000000ffffffff", // validUntil
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("got  %x\nwant %x", got, want)
+	}
+	if len(got) != forcedExitLength {
+		t.Errorf("length %d, want forcedExitLength %d", len(got), forcedExitLength)
+	}
+}
+
+func TestWithAddressPrefix(t *testing.T) {
+	want, err := SerializeTransfer(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := testTransfer()
+	tx.To = "zk:" + strings.TrimPrefix(testTo, "0x")
+	got, err := SerializeTransfer(tx, WithAddressPrefix("zk:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("zk: address serialized to %x, want %x", got, want)
+	}
+
+	// The prefix only applies to the call it was given to.
+	if _, err := SerializeTransfer(tx); !errors.Is(err, ErrBadAddressPrefix) {
+		t.Errorf("without the option got %v, want ErrBadAddressPrefix", err)
+	}
+	_, err = SerializeTransfer(tx, WithAddressPrefix("fork:"))
+	if !errors.Is(err, ErrBadAddressPrefix) || !strings.Contains(err.Error(), "fork:") {
+		t.Errorf("with another prefix got %v, want ErrBadAddressPrefix naming fork:", err)
+	}
+}
+
+func TestSuggestedValidWindow(t *testing.T) {
+	now := time.Date(2021, 5, 4, 12, 0, 0, 0, time.UTC)
+	from, until := SuggestedValidWindow(now)
+	if from != 0 {
+		t.Errorf("from = %d, want 0", from)
+	}
+	if want := uint64(now.Unix()) + 3600; until != want {
+		t.Errorf("until = %d, want %d, an hour after now", until, want)
+	}
+}
+
+func TestTokenIdRanges(t *testing.T) {
+	// An NFT where a fungible token is expected.
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	withdraw.Token = MIN_NFT_TOKEN_ID
+	if _, err := SerializeWithdraw(withdraw, WithProtocolVersion(ProtocolV2)); err == nil {
+		t.Error("Withdraw of an NFT serialized without an error")
+	}
+	forcedExit := testForcedExit()
+	forcedExit.Token = MIN_NFT_TOKEN_ID
+	if _, err := SerializeForcedExit(forcedExit, WithProtocolVersion(ProtocolV2)); err == nil {
+		t.Error("ForcedExit of an NFT serialized without an error")
+	}
+	changePubKey := testChangePubKey()
+	changePubKey.FeeToken = MIN_NFT_TOKEN_ID
+	if _, err := SerializeChangePubKey(changePubKey, WithProtocolVersion(ProtocolV2)); err == nil {
+		t.Error("NFT fee token serialized without an error")
+	}
+	if _, err := appendFungibleTokenId(nil, MAX_NUMBER_OF_TOKENS, ProtocolV2); !errors.Is(err, ErrTokenIdTooBig) {
+		t.Errorf("fungible id %d: got %v, want ErrTokenIdTooBig", MAX_NUMBER_OF_TOKENS, err)
+	}
+
+	// A fungible token where an NFT is expected.
+	if _, err := appendNFTTokenId(nil, 3, ProtocolV2); err == nil {
+		t.Error("fungible id 3 accepted as an NFT")
+	}
+	if _, err := appendNFTTokenId(nil, MAX_NFT_TOKEN_ID+1, ProtocolV2); !errors.Is(err, ErrTokenIdTooBig) {
+		t.Errorf("NFT id 2^32: got %v, want ErrTokenIdTooBig", err)
+	}
+
+	// Transfer takes either, but NFT ids need the 4-byte encoding.
+	nft := testTransfer()
+	nft.Token = MAX_NFT_TOKEN_ID
+	got, err := SerializeTransfer(nft, WithProtocolVersion(ProtocolV2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenField := got[2+4+20+20:][:4]; !bytes.Equal(tokenField, []byte{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("NFT token field %x, want ffffffff", tokenField)
+	}
+	if _, err := SerializeTransfer(nft); err == nil {
+		t.Error("NFT transfer serialized in the 2-byte ProtocolV1 layout")
+	}
+}
+
+func TestPackedSizes(t *testing.T) {
+	if n := PackedAmountSize(); n != 5 {
+		t.Errorf("PackedAmountSize() = %d, want 5", n)
+	}
+	if n := PackedFeeSize(); n != 2 {
+		t.Errorf("PackedFeeSize() = %d, want 2", n)
+	}
+	for _, v := range []string{"0", "1", "1500000000000000000"} {
+		amount, err := PackAmount(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(amount) != PackedAmountSize() {
+			t.Errorf("PackAmount(%s) is %d bytes, PackedAmountSize() is %d", v, len(amount), PackedAmountSize())
+		}
+		fee, err := PackFee(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fee) != PackedFeeSize() {
+			t.Errorf("PackFee(%s) is %d bytes, PackedFeeSize() is %d", v, len(fee), PackedFeeSize())
+		}
+	}
+}
+
+func TestWithMemo(t *testing.T) {
+	plain, err := SerializeTx(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{0, 1, MaxMemoLength} {
+		tx := testTransfer()
+		tx.Memo = bytes.Repeat([]byte{'m'}, n)
+		got, err := SerializeTx(tx, WithMemo())
+		if err != nil {
+			t.Errorf("%d-byte memo: %v", n, err)
+			continue
+		}
+		want := append(append(append([]byte(nil), plain...), byte(n)), tx.Memo...)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%d-byte memo: got %x, want %x", n, got, want)
+		}
+	}
+
+	tx := testTransfer()
+	tx.Memo = make([]byte, MaxMemoLength+1)
+	if _, err := SerializeTx(tx, WithMemo()); err == nil {
+		t.Errorf("%d-byte memo accepted", len(tx.Memo))
+	}
+	// Without WithMemo the memo is not part of the serialization.
+	if got, err := SerializeTx(tx); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("memo serialized without WithMemo: %x, %v", got, err)
+	}
+}
+
+func TestSerializeTimestamps(t *testing.T) {
+	tests := []struct {
+		ts   uint64
+		want string
+	}{
+		{0, "0000000000000000"},
+		{4294967295, "00000000ffffffff"},
+		// One past the 4-byte range must not wrap.
+		{4294967296, "0000000100000000"},
+		{math.MaxUint64, "ffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		tx := testTransfer()
+		tx.ValidFrom = tt.ts
+		tx.ValidUntil = tt.ts
+		ser, err := SerializeTx(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// validFrom and validUntil are the last 16 bytes.
+		got := hex.EncodeToString(ser[len(ser)-16:])
+		if got != tt.want+tt.want {
+			t.Errorf("%d: got %s, want %s twice", tt.ts, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTypeByte(t *testing.T) {
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	txs := []*Tx{testTransfer(), withdraw, testChangePubKey(), testForcedExit()}
+	for _, version := range []ProtocolVersion{ProtocolV1, ProtocolV2} {
+		for _, tx := range txs {
+			data, err := SerializeTx(tx, WithProtocolVersion(version))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, declared := range txs {
+				err := VerifyTypeByte(data, declared)
+				if declared.Type == tx.Type && err != nil {
+					t.Errorf("v%d %s bytes declared %s: %v", version, tx.Type, declared.Type, err)
+				}
+				if declared.Type != tx.Type && err == nil {
+					t.Errorf("v%d %s bytes declared %s: no error", version, tx.Type, declared.Type)
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyTypeByteInvalid(t *testing.T) {
+	tx := testTransfer()
+	tests := map[string][]byte{
+		"empty":            nil,
+		"unknown type":     {0x42},
+		"V2 marker alone":  {0xfa},
+		"V2 wrong version": {0xfa, 0x02},
+	}
+	for name, data := range tests {
+		if err := VerifyTypeByte(data, tx); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	unknown := testTransfer()
+	unknown.Type = "Swap"
+	if err := VerifyTypeByte([]byte{0x05}, unknown); err == nil {
+		t.Error("unknown declared type: no error")
+	}
+}
+
+// TestSerializeTransferGolden pins the bytes of the preallocated transfer
+// serializer to the output of the append-per-field implementation it
+// replaced.
+func TestSerializeTransferGolden(t *testing.T) {
+	bigAmount, _ := new(big.Int).SetString("340000000000000000000000000000", 10)
+	tests := []struct {
+		name string
+		edit func(tx *Tx)
+		opts []SerializeOption
+		want string
+	}{
+		{
+			name: "v1",
+			want: "050000000736615cf349d7f6344891b1e7ca7c72883f5dc049123456781234567812345678123456781234567800036fc23ac0082eeb0000000c000000000000000000000000ffffffff",
+		},
+		{
+			name: "v2",
+			opts: []SerializeOption{WithProtocolVersion(ProtocolV2)},
+			want: "fa010000000736615cf349d7f6344891b1e7ca7c72883f5dc0491234567812345678123456781234567812345678000000036fc23ac0082eeb0000000c000000000000000000000000ffffffff",
+		},
+		{
+			name: "memo",
+			edit: func(tx *Tx) { tx.Memo = []byte("hi") },
+			opts: []SerializeOption{WithMemo()},
+			want: "050000000736615cf349d7f6344891b1e7ca7c72883f5dc049123456781234567812345678123456781234567800036fc23ac0082eeb0000000c000000000000000000000000ffffffff026869",
+		},
+		{
+			name: "amount beyond uint64",
+			edit: func(tx *Tx) { tx.Amount = NewBigInt(bigAmount) },
+			want: "050000000736615cf349d7f6344891b1e7ca7c72883f5dc04912345678123456781234567812345678123456780003fd51da80132eeb0000000c000000000000000000000000ffffffff",
+		},
+		{
+			name: "nft",
+			edit: func(tx *Tx) { tx.Token = 70000 },
+			opts: []SerializeOption{WithProtocolVersion(ProtocolV2)},
+			want: "fa010000000736615cf349d7f6344891b1e7ca7c72883f5dc0491234567812345678123456781234567812345678000111706fc23ac0082eeb0000000c000000000000000000000000ffffffff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testTransfer()
+			if tt.edit != nil {
+				tt.edit(tx)
+			}
+			got, err := SerializeTransfer(tx, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("got  %x\nwant %s", got, tt.want)
+			}
+			if len(got) != cap(got) {
+				t.Errorf("len %d, cap %d: the buffer was not sized exactly", len(got), cap(got))
+			}
+		})
+	}
+}
+
+func BenchmarkSerializeTransfer(b *testing.B) {
+	tx := testTransfer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := SerializeTransfer(tx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSerializeTx(b *testing.B) {
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	txs := []*Tx{testTransfer(), withdraw, testChangePubKey(), testForcedExit()}
+	for _, tx := range txs {
+		b.Run(string(tx.Type), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := SerializeTx(tx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// shortWriter accepts at most n bytes per call without reporting an error.
+type shortWriter struct {
+	n       int
+	written []byte
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		p = p[:w.n]
+	}
+	w.written = append(w.written, p...)
+	return len(p), nil
+}
+
+// failingWriter fails every write.
+type failingWriter struct {
+	err   error
+	calls int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.calls++
+	return 0, w.err
+}
+
+func TestSerializeTxTo(t *testing.T) {
+	want, err := SerializeTx(testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := SerializeTxTo(&buf, testTransfer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(want) || !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("wrote %d bytes %x, want %d bytes %x", n, buf.Bytes(), len(want), want)
+	}
+}
+
+func TestSerializeTxToShortWrite(t *testing.T) {
+	w := &shortWriter{n: 10}
+	n, err := SerializeTxTo(w, testTransfer())
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("got %v, want io.ErrShortWrite", err)
+	}
+	if n != 10 || len(w.written) != 10 {
+		t.Errorf("reported %d bytes, writer got %d, want 10", n, len(w.written))
+	}
+}
+
+func TestSerializeTxToWriterError(t *testing.T) {
+	errDisk := errors.New("disk full")
+	w := &failingWriter{err: errDisk}
+	if _, err := SerializeTxTo(w, testTransfer()); !errors.Is(err, errDisk) {
+		t.Errorf("got %v, want the writer's error", err)
+	}
+
+	// An invalid transaction fails before anything reaches the writer.
+	tx := testTransfer()
+	tx.To = "0x1234"
+	w = &failingWriter{err: errDisk}
+	n, err := SerializeTxTo(w, tx)
+	if err == nil || errors.Is(err, errDisk) {
+		t.Errorf("got %v, want the serialization error", err)
+	}
+	if n != 0 || w.calls != 0 {
+		t.Errorf("%d bytes in %d writes for an invalid transaction, want none", n, w.calls)
+	}
+}
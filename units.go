@@ -0,0 +1,76 @@
+package zinc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ParseUnits converts a decimal string in token units into base units using
+// the token's decimals. "1.5", "1" and ".5" are accepted; more fractional
+// digits than decimals is an error.
+func ParseUnits(s string, decimals int) (*big.Int, error) {
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" {
+		return nil, fmt.Errorf("Invalid decimal amount: %q", s)
+	}
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("Too many decimal places in %q: token has %d decimals", s, decimals)
+	}
+	digits := whole + frac + strings.Repeat("0", decimals-len(frac))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("Invalid decimal amount: %q", s)
+		}
+	}
+	value, _ := new(big.Int).SetString(digits, 10)
+	return value, nil
+}
+
+// SetAmountDecimal sets tx.Amount from an amount in token units, e.g. "1.5"
+// ETH with 18 decimals.
+func (tx *Tx) SetAmountDecimal(s string, decimals int) error {
+	amount, err := ParseUnits(s, decimals)
+	if err != nil {
+		return err
+	}
+	tx.Amount = NewBigInt(amount)
+	return nil
+}
+
+// FormatUnits renders a base-unit amount in token units for display, with
+// trailing fractional zeros trimmed: 1.5 ETH is "1.5", 1 ETH is "1" and zero
+// is "0".
+func FormatUnits(value *big.Int, decimals int) string {
+	sign := ""
+	if value.Sign() < 0 {
+		sign, value = "-", new(big.Int).Neg(value)
+	}
+	s := value.String()
+	if decimals <= 0 {
+		return sign + s
+	}
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// formatUnits renders an amount the way ethers formatUnits does, which is
+// what zkSync wallets show and sign: the fraction keeps at least one digit,
+// so 1 ETH is "1.0" and zero is "0.0". The Ethereum messages must use this
+// form, not FormatUnits, or their signatures will not match zksync.js.
+func formatUnits(value *big.Int, decimals int) string {
+	s := FormatUnits(value, decimals)
+	if decimals > 0 && !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
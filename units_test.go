@@ -0,0 +1,120 @@
+package zinc
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSetAmountDecimal(t *testing.T) {
+	tx := testTransfer()
+	if err := tx.SetAmountDecimal("1.5", 18); err != nil {
+		t.Fatal(err)
+	}
+	if want := big.NewInt(1500000000000000000); tx.Amount.Value().Cmp(want) != 0 {
+		t.Errorf("amount %s, want %s", tx.Amount.Value(), want)
+	}
+	if err := tx.SetAmountDecimal("2.5", 6); err != nil {
+		t.Fatal(err)
+	}
+	if want := big.NewInt(2500000); tx.Amount.Value().Cmp(want) != 0 {
+		t.Errorf("amount %s, want %s", tx.Amount.Value(), want)
+	}
+
+	// Seven fractional digits cannot be expressed with 6 decimals.
+	before := tx.Amount.Value()
+	if err := tx.SetAmountDecimal("1.0000001", 6); err == nil {
+		t.Error("over-precise amount accepted")
+	}
+	if tx.Amount.Value().Cmp(before) != 0 {
+		t.Errorf("failed call changed the amount to %s", tx.Amount.Value())
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		s        string
+		decimals int
+		want     string
+	}{
+		{"1.5", 18, "1500000000000000000"},
+		{"1", 18, "1000000000000000000"},
+		{".5", 18, "500000000000000000"},
+		{"1.", 6, "1000000"},
+		{"0", 18, "0"},
+		{"0.000001", 6, "1"},
+		{"1.500000", 6, "1500000"},
+		{"42", 0, "42"},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnits(tt.s, tt.decimals)
+		if err != nil {
+			t.Errorf("ParseUnits(%q, %d): %v", tt.s, tt.decimals, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseUnits(%q, %d) = %s, want %s", tt.s, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnitsErrors(t *testing.T) {
+	tests := []struct {
+		s        string
+		decimals int
+	}{
+		{"1.0000001", 6},
+		{"0.5", 0},
+		{"", 18},
+		{".", 18},
+		{"-1", 18},
+		{"1.2.3", 18},
+		{"1e18", 18},
+		{" 1", 18},
+		{"1,5", 18},
+	}
+	for _, tt := range tests {
+		if got, err := ParseUnits(tt.s, tt.decimals); err == nil {
+			t.Errorf("ParseUnits(%q, %d) = %s, want an error", tt.s, tt.decimals, got)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals int
+		want     string
+	}{
+		{"0", 18, "0"},
+		{"1500000000000000000", 18, "1.5"},
+		{"1000000000000000000", 18, "1"},
+		{"37500000000000", 18, "0.0000375"},
+		{"1", 18, "0.000000000000000001"},
+		{"2500000", 6, "2.5"},
+		{"120", 2, "1.2"},
+		{"42", 0, "42"},
+		{"-1500000", 6, "-1.5"},
+	}
+	for _, tt := range tests {
+		value := mustDecimal(t, strings.TrimPrefix(tt.value, "-"))
+		if strings.HasPrefix(tt.value, "-") {
+			value.Neg(value)
+		}
+		if got := FormatUnits(value, tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%s, %d) = %s, want %s", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestUnitsRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "1", "1.5", "0.000001", "123456.789"} {
+		value, err := ParseUnits(s, 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FormatUnits(value, 6); got != s {
+			t.Errorf("%s came back as %s", s, got)
+		}
+	}
+}
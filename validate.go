@@ -0,0 +1,113 @@
+package zinc
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// CheckAffordable reports an error if the transaction costs more than the
+// sender holds. balance is the sender's balance of tx.Token. When the fee is
+// paid in another token, as with a ChangePubKey whose FeeToken differs from
+// Token, pass the sender's balance of that token as feeBalance: the amount is
+// then checked against balance and the fee against feeBalance. Otherwise
+// amount + fee is checked against balance.
+func (tx *Tx) CheckAffordable(balance *big.Int, feeBalance ...*big.Int) error {
+	if balance == nil {
+		return fmt.Errorf("Missing balance")
+	}
+	switch len(feeBalance) {
+	case 0:
+		total := new(big.Int).Add(tx.Amount.Value(), tx.Fee.Value())
+		if total.Cmp(balance) > 0 {
+			return fmt.Errorf("Insufficient balance: amount + fee is %s, balance is %s", total, balance)
+		}
+	case 1:
+		if feeBalance[0] == nil {
+			return fmt.Errorf("Missing fee balance")
+		}
+		if amount := tx.Amount.Value(); amount.Cmp(balance) > 0 {
+			return fmt.Errorf("Insufficient balance: amount is %s, balance is %s", amount, balance)
+		}
+		if fee := tx.Fee.Value(); fee.Cmp(feeBalance[0]) > 0 {
+			return fmt.Errorf("Insufficient fee balance: fee is %s, balance is %s", fee, feeBalance[0])
+		}
+	default:
+		return fmt.Errorf("Expected at most one fee balance, got %d", len(feeBalance))
+	}
+	return nil
+}
+
+// ValidateOption configures the checks performed by Tx.Validate.
+type ValidateOption func(*validateConfig)
+
+type validateConfig struct {
+	minFee    *big.Int
+	allowBurn bool
+}
+
+// WithMinFee rejects transactions whose fee is below minFee.
+func WithMinFee(minFee *big.Int) ValidateOption {
+	return func(c *validateConfig) {
+		c.minFee = minFee
+	}
+}
+
+// WithAllowBurn permits transfers to the zero address.
+func WithAllowBurn() ValidateOption {
+	return func(c *validateConfig) {
+		c.allowBurn = true
+	}
+}
+
+// FieldError is a problem with one field of a transaction. Field is the JSON
+// field name.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors is every problem Validate found. It marshals to JSON as
+// [{"field":"to","message":"..."}], ready to show next to the form fields.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks tx for problems that would get it rejected on submission.
+// A failure is returned as ValidationErrors listing every problem found.
+func (tx *Tx) Validate(opts ...ValidateOption) error {
+	var cfg validateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var errs ValidationErrors
+	fee := tx.Fee.Value()
+	if cfg.minFee != nil && fee.Cmp(cfg.minFee) < 0 {
+		errs = append(errs, FieldError{"fee", fmt.Sprintf("Fee %s is below the minimum fee %s", fee, cfg.minFee)})
+	}
+	if tx.Type == TxTypeTransfer && !cfg.allowBurn {
+		to, err := serializeAddress(tx.To)
+		if err != nil {
+			errs = append(errs, FieldError{"to", err.Error()})
+		} else if bytes.Equal(to, zeroAddress[:]) {
+			errs = append(errs, FieldError{"to", "Transfer to the zero address burns the funds"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+var zeroAddress [20]byte
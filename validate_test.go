@@ -0,0 +1,79 @@
+package zinc
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestValidationErrorsJSON(t *testing.T) {
+	tx := testTransfer()
+	tx.To = "0x0000000000000000000000000000000000000000"
+	tx.Fee = NewBigInt(big.NewInt(1))
+	err := tx.Validate(WithMinFee(big.NewInt(100)))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %v, want ValidationErrors", err)
+	}
+	got, err := json.Marshal(errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"field":"fee","message":"Fee 1 is below the minimum fee 100"},` +
+		`{"field":"to","message":"Transfer to the zero address burns the funds"}]`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := testTransfer().Validate(); err != nil {
+		t.Errorf("valid transfer: %v", err)
+	}
+	burn := testTransfer()
+	burn.To = "0x0000000000000000000000000000000000000000"
+	if err := burn.Validate(WithAllowBurn()); err != nil {
+		t.Errorf("burn with WithAllowBurn: %v", err)
+	}
+}
+
+func TestCheckAffordable(t *testing.T) {
+	// testTransfer sends 1.5e18 with a fee of 3.75e13.
+	tx := testTransfer()
+	total := new(big.Int).Add(tx.Amount.Value(), tx.Fee.Value())
+	tests := []struct {
+		name       string
+		balance    *big.Int
+		feeBalance []*big.Int
+		ok         bool
+	}{
+		{"exact", total, nil, true},
+		{"more", new(big.Int).Mul(total, big.NewInt(2)), nil, true},
+		{"one short", new(big.Int).Sub(total, big.NewInt(1)), nil, false},
+		{"amount only", tx.Amount.Value(), nil, false},
+		{"nil balance", nil, nil, false},
+		{"separate fee balance", tx.Amount.Value(), []*big.Int{tx.Fee.Value()}, true},
+		{"fee balance short", tx.Amount.Value(), []*big.Int{big.NewInt(1)}, false},
+		{"amount short with fee balance", big.NewInt(1), []*big.Int{total}, false},
+		{"nil fee balance", total, []*big.Int{nil}, false},
+		{"two fee balances", total, []*big.Int{total, total}, false},
+	}
+	for _, tt := range tests {
+		err := tx.CheckAffordable(tt.balance, tt.feeBalance...)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestCheckAffordableChangePubKey(t *testing.T) {
+	// The fee is paid in FeeToken, so the balance of Token does not matter.
+	tx := testChangePubKey()
+	if err := tx.CheckAffordable(new(big.Int), tx.Fee.Value()); err != nil {
+		t.Errorf("fee covered by the fee balance: %v", err)
+	}
+	if err := tx.CheckAffordable(tx.Fee.Value(), new(big.Int)); err == nil {
+		t.Error("empty fee balance accepted")
+	}
+}
@@ -0,0 +1,171 @@
+package zinc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// zkSyncJSTx is the shape of a signed transaction object in zksync.js.
+// Amounts may be ethers BigNumbers, which JSON.stringify renders as
+// {"type":"BigNumber","hex":"0x..."}. Which fields are present depends on
+// the type, so the numeric ones that may be zero are pointers.
+type zkSyncJSTx struct {
+	Type               TxType                `json:"type"`
+	AccountId          *uint64               `json:"accountId,omitempty"`
+	InitiatorAccountId *uint64               `json:"initiatorAccountId,omitempty"`
+	From               string                `json:"from,omitempty"`
+	To                 string                `json:"to,omitempty"`
+	Account            string                `json:"account,omitempty"`
+	NewPkHash          string                `json:"newPkHash,omitempty"`
+	Target             string                `json:"target,omitempty"`
+	Token              *uint64               `json:"token,omitempty"`
+	FeeToken           *uint64               `json:"feeToken,omitempty"`
+	Amount             json.RawMessage       `json:"amount,omitempty"`
+	Fee                json.RawMessage       `json:"fee"`
+	Nonce              uint64                `json:"nonce"`
+	Signature          *Signature            `json:"signature,omitempty"`
+	EthAuthData        *ChangePubKeyAuthData `json:"ethAuthData,omitempty"`
+	ValidFrom          uint64                `json:"validFrom"`
+	ValidUntil         uint64                `json:"validUntil"`
+}
+
+// TxFromZkSyncJS parses a transaction object produced by zksync.js.
+func TxFromZkSyncJS(jsonBytes []byte) (*Tx, error) {
+	var js zkSyncJSTx
+	if err := json.Unmarshal(jsonBytes, &js); err != nil {
+		return nil, err
+	}
+	fee, err := parseBigNumberish(js.Fee)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid fee: %w", err)
+	}
+	tx := &Tx{
+		Type:       js.Type,
+		Fee:        NewBigInt(fee),
+		Nonce:      js.Nonce,
+		ValidFrom:  js.ValidFrom,
+		ValidUntil: js.ValidUntil,
+	}
+	switch js.Type {
+	case TxTypeTransfer, TxTypeWithdraw:
+		amount, err := parseBigNumberish(js.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid amount: %w", err)
+		}
+		tx.AccountId = uint64Value(js.AccountId)
+		tx.From = js.From
+		tx.To = js.To
+		tx.Token = uint64Value(js.Token)
+		tx.Amount = NewBigInt(amount)
+	case TxTypeChangePubKey:
+		tx.AccountId = uint64Value(js.AccountId)
+		tx.Account = js.Account
+		tx.NewPkHash = js.NewPkHash
+		tx.FeeToken = uint64Value(js.FeeToken)
+		tx.EthAuthData = js.EthAuthData
+	case TxTypeForcedExit:
+		tx.InitiatorAccountId = uint64Value(js.InitiatorAccountId)
+		tx.Target = js.Target
+		tx.Token = uint64Value(js.Token)
+	default:
+		return nil, fmt.Errorf("Unknown transaction type: %q", js.Type)
+	}
+	if js.Signature != nil {
+		tx.Signature = *js.Signature
+	}
+	return tx, nil
+}
+
+// TxToZkSyncJS encodes tx as a zksync.js transaction object with the fields
+// of its type. Amounts are written as decimal strings, which BigNumber.from
+// accepts.
+func TxToZkSyncJS(tx *Tx) ([]byte, error) {
+	fee, err := json.Marshal(tx.Fee)
+	if err != nil {
+		return nil, err
+	}
+	js := zkSyncJSTx{
+		Type:       tx.Type,
+		Fee:        fee,
+		Nonce:      tx.Nonce,
+		ValidFrom:  tx.ValidFrom,
+		ValidUntil: tx.ValidUntil,
+	}
+	switch tx.Type {
+	case TxTypeTransfer, TxTypeWithdraw:
+		amount, err := json.Marshal(tx.Amount)
+		if err != nil {
+			return nil, err
+		}
+		js.AccountId = &tx.AccountId
+		js.From = tx.From
+		js.To = tx.To
+		js.Token = &tx.Token
+		js.Amount = amount
+	case TxTypeChangePubKey:
+		js.AccountId = &tx.AccountId
+		js.Account = tx.Account
+		js.NewPkHash = tx.NewPkHash
+		js.FeeToken = &tx.FeeToken
+		js.EthAuthData = tx.EthAuthData
+	case TxTypeForcedExit:
+		js.InitiatorAccountId = &tx.InitiatorAccountId
+		js.Target = tx.Target
+		js.Token = &tx.Token
+	default:
+		return nil, fmt.Errorf("Unknown transaction type: %q", tx.Type)
+	}
+	if tx.Signature != (Signature{}) {
+		js.Signature = &tx.Signature
+	}
+	return json.Marshal(js)
+}
+
+func uint64Value(p *uint64) uint64 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+// parseBigNumberish accepts the forms zksync.js emits for a BigNumberish:
+// a decimal or 0x-prefixed hex string, a JSON number, or a serialized ethers
+// BigNumber object.
+func parseBigNumberish(raw json.RawMessage) (*big.Int, error) {
+	var bn struct {
+		Type string `json:"type"`
+		Hex  string `json:"hex"`
+	}
+	var s string
+	switch {
+	case len(raw) == 0:
+		return nil, fmt.Errorf("missing value")
+	case raw[0] == '{':
+		if err := json.Unmarshal(raw, &bn); err != nil {
+			return nil, err
+		}
+		if bn.Type != "BigNumber" {
+			return nil, fmt.Errorf("unexpected object type %q", bn.Type)
+		}
+		s = bn.Hex
+	case raw[0] == '"':
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+	default:
+		s = string(raw)
+	}
+	var value *big.Int
+	var ok bool
+	if strings.HasPrefix(s, "0x") {
+		value, ok = new(big.Int).SetString(s[2:], 16)
+	} else {
+		value, ok = new(big.Int).SetString(s, 10)
+	}
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("not a non-negative integer: %s", s)
+	}
+	return value, nil
+}
@@ -0,0 +1,57 @@
+package zinc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestZkSyncJSRoundTrip(t *testing.T) {
+	withdraw := testTransfer()
+	withdraw.Type = TxTypeWithdraw
+	cpk := testChangePubKey()
+	cpk.EthAuthData = &ChangePubKeyAuthData{Type: ChangePubKeyOnchain}
+	transfer := testTransfer()
+	transfer.Signature = *testL2Signature()
+	// Account 0 and token 0 must survive even though they are zero.
+	transfer.AccountId = 0
+	transfer.Token = 0
+	for _, tx := range []*Tx{transfer, withdraw, cpk, testForcedExit()} {
+		t.Run(string(tx.Type), func(t *testing.T) {
+			js, err := TxToZkSyncJS(tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := TxFromZkSyncJS(js)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tx) {
+				t.Errorf("round trip through %s\ngot  %+v\nwant %+v", js, got, tx)
+			}
+		})
+	}
+}
+
+func TestTxFromZkSyncJSBigNumber(t *testing.T) {
+	js := `{"type":"Transfer","accountId":7,"from":"` + testFrom + `","to":"` + testTo + `","token":3,` +
+		`"amount":{"type":"BigNumber","hex":"0x14d1120d7b160000"},"fee":"37500000000000","nonce":12,` +
+		`"validFrom":0,"validUntil":4294967295}`
+	got, err := TxFromZkSyncJS([]byte(js))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, testTransfer()) {
+		t.Errorf("got %+v, want %+v", got, testTransfer())
+	}
+}
+
+func TestZkSyncJSUnknownType(t *testing.T) {
+	tx := testTransfer()
+	tx.Type = "Swap"
+	if _, err := TxToZkSyncJS(tx); err == nil {
+		t.Error("TxToZkSyncJS accepted an unknown type")
+	}
+	if _, err := TxFromZkSyncJS([]byte(`{"type":"Swap","fee":"0"}`)); err == nil {
+		t.Error("TxFromZkSyncJS accepted an unknown type")
+	}
+}